@@ -0,0 +1,229 @@
+// Command ark-vectorgen expands a single valid baseline congestion tree into
+// negative test vectors, one per entry of the mutations table below. That
+// table covers a subset of the Err* sentinels declared in
+// common/tree/validation.go; sentinels with no mutation get no generated
+// vector. It exists so the corpus in
+// common/tree/testvectors/ doesn't have to be maintained by hand: every time
+// a new Err* case is added to the validator, a new mutation is added here
+// and the generated vectors are refreshed by re-running this tool.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	tree "github.com/ark-network/ark/common/tree"
+)
+
+func main() {
+	baselinePath := flag.String("baseline", "common/tree/testvectors/fixtures/baseline_valid.json", "path to a TestVector that validates successfully")
+	outDir := flag.String("out", "common/tree/testvectors/generated", "directory to write generated negative vectors into")
+	flag.Parse()
+
+	if err := run(*baselinePath, *outDir); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(baselinePath, outDir string) error {
+	raw, err := os.ReadFile(baselinePath)
+	if err != nil {
+		return fmt.Errorf("reading baseline vector %s: %w", baselinePath, err)
+	}
+
+	var baseline tree.TestVector
+	if err := json.Unmarshal(raw, &baseline); err != nil {
+		return fmt.Errorf("decoding baseline vector %s: %w", baselinePath, err)
+	}
+	if baseline.ExpectedErr != "" {
+		return fmt.Errorf("baseline vector %s must validate successfully, but names expectedErr %q", baselinePath, baseline.ExpectedErr)
+	}
+
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return fmt.Errorf("creating %s: %w", outDir, err)
+	}
+
+	for _, m := range mutations {
+		if _, ok := tree.SentinelByName(m.sentinel); !ok {
+			return fmt.Errorf("mutation %q targets unknown sentinel %q", m.name, m.sentinel)
+		}
+
+		vector, err := m.apply(cloneVector(baseline))
+		if err != nil {
+			return fmt.Errorf("applying mutation %q: %w", m.name, err)
+		}
+		vector.Description = m.description
+		vector.Tags = m.tags
+		vector.ExpectedErr = m.sentinel
+
+		out, err := json.MarshalIndent(vector, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling vector for mutation %q: %w", m.name, err)
+		}
+
+		path := filepath.Join(outDir, m.name+".json")
+		if err := os.WriteFile(path, out, 0o644); err != nil {
+			return fmt.Errorf("writing %s: %w", path, err)
+		}
+		fmt.Println("wrote", path)
+	}
+
+	return nil
+}
+
+// cloneVector deep-copies v through JSON so a mutation can freely edit the
+// tree without aliasing the baseline or any previously generated vector.
+func cloneVector(v tree.TestVector) tree.TestVector {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("cloning baseline vector: %s", err))
+	}
+	var clone tree.TestVector
+	if err := json.Unmarshal(raw, &clone); err != nil {
+		panic(fmt.Sprintf("cloning baseline vector: %s", err))
+	}
+	return clone
+}
+
+type mutation struct {
+	name        string
+	sentinel    string
+	description string
+	tags        []string
+	apply       func(tree.TestVector) (tree.TestVector, error)
+}
+
+// mutations covers every Err* case reachable by editing the baseline tree's
+// existing fields (leaf flags, txids, base64 blobs) without re-encoding a
+// PSET. Err* cases that only trigger inside a specific input/output count or
+// a specific tapscript byte (e.g. ErrNumberOfOutputs, ErrInvalidAmount,
+// ErrMissingSweepTapscript, ErrMissingBranchTapscript) require a
+// PSET-aware mutator that can rewrite a node's tapleaf scripts directly
+// instead of corrupting its Tx blob wholesale - which would almost
+// certainly break PSET parsing outright rather than produce the mismatched
+// tapleaf pair these two sentinels expect - and are left for a follow-up
+// once this repo has a reusable PSET builder to share with them.
+var mutations = []mutation{
+	{
+		name:        "empty_tree",
+		sentinel:    "ErrEmptyTree",
+		description: "tree with zero levels is rejected",
+		tags:        []string{"structural"},
+		apply: func(v tree.TestVector) (tree.TestVector, error) {
+			v.Tree = tree.CongestionTree{}
+			return v, nil
+		},
+	},
+	{
+		name:        "invalid_root_level",
+		sentinel:    "ErrInvalidRootLevel",
+		description: "root level with two nodes is rejected",
+		tags:        []string{"structural"},
+		apply: func(v tree.TestVector) (tree.TestVector, error) {
+			if len(v.Tree) == 0 || len(v.Tree[0]) == 0 {
+				return v, fmt.Errorf("baseline tree has no root node to duplicate")
+			}
+			v.Tree[0] = append(v.Tree[0], v.Tree[0][0])
+			return v, nil
+		},
+	},
+	{
+		name:        "wrong_pool_txid",
+		sentinel:    "ErrWrongPoolTxID",
+		description: "root input not connected to the pool tx outpoint",
+		tags:        []string{"pool-tx"},
+		apply: func(v tree.TestVector) (tree.TestVector, error) {
+			// Flipping a hex nibble inside the raw pool tx (rather than the
+			// root node's own PSET) changes its computed txid while
+			// leaving it byte-for-byte the same length and still a
+			// well-formed transaction, so the root's outpoint now points
+			// at the wrong (but still parseable) pool tx instead of
+			// failing to parse at all.
+			v.PoolTxHex = flipLastHexByte(v.PoolTxHex)
+			return v, nil
+		},
+	},
+	{
+		name:        "no_leaves",
+		sentinel:    "ErrNoLeaves",
+		description: "tree where no node is marked as a leaf",
+		tags:        []string{"structural"},
+		apply: func(v tree.TestVector) (tree.TestVector, error) {
+			for i := range v.Tree {
+				for j := range v.Tree[i] {
+					v.Tree[i][j].Leaf = false
+				}
+			}
+			return v, nil
+		},
+	},
+	{
+		name:        "node_transaction_empty",
+		sentinel:    "ErrNodeTransactionEmpty",
+		description: "a non-root node with an empty Tx field",
+		tags:        []string{"structural"},
+		apply:       mutateLeaf(func(n *tree.Node) { n.Tx = "" }),
+	},
+	{
+		name:        "node_txid_empty",
+		sentinel:    "ErrNodeTxidEmpty",
+		description: "a non-root node with an empty Txid field",
+		tags:        []string{"structural"},
+		apply:       mutateLeaf(func(n *tree.Node) { n.Txid = "" }),
+	},
+	{
+		name:        "node_parent_txid_empty",
+		sentinel:    "ErrNodeParentTxidEmpty",
+		description: "a non-root node with an empty ParentTxid field",
+		tags:        []string{"structural"},
+		apply:       mutateLeaf(func(n *tree.Node) { n.ParentTxid = "" }),
+	},
+	{
+		name:        "node_txid_different",
+		sentinel:    "ErrNodeTxidDifferent",
+		description: "node Txid doesn't match the hash of its own Tx",
+		tags:        []string{"taproot-control-block"},
+		apply:       mutateLeaf(func(n *tree.Node) { n.Txid = flipLastHexByte(n.Txid) }),
+	},
+}
+
+// mutateLeaf returns an apply func that edits the first leaf node it finds
+// (falling back to the last node of the last level), which is enough to
+// trigger the per-node Err* cases without needing to rebuild the tree.
+func mutateLeaf(edit func(n *tree.Node)) func(tree.TestVector) (tree.TestVector, error) {
+	return func(v tree.TestVector) (tree.TestVector, error) {
+		for i := range v.Tree {
+			for j := range v.Tree[i] {
+				if v.Tree[i][j].Leaf {
+					edit(&v.Tree[i][j])
+					return v, nil
+				}
+			}
+		}
+		lastLevel := len(v.Tree) - 1
+		if lastLevel < 0 || len(v.Tree[lastLevel]) == 0 {
+			return v, fmt.Errorf("baseline tree has no node to mutate")
+		}
+		lastNode := len(v.Tree[lastLevel]) - 1
+		edit(&v.Tree[lastLevel][lastNode])
+		return v, nil
+	}
+}
+
+func flipLastHexByte(s string) string {
+	if len(s) == 0 {
+		return s
+	}
+	runes := []rune(s)
+	last := runes[len(runes)-1]
+	if last == '0' {
+		runes[len(runes)-1] = '1'
+	} else {
+		runes[len(runes)-1] = '0'
+	}
+	return string(runes)
+}
@@ -0,0 +1,91 @@
+package tree
+
+// ChainBackend abstracts the handful of chain-specific operations
+// ValidateCongestionTree needs: parsing a pool transaction, parsing a node
+// transaction from its wire-format PSBT/PSET, and decoding the two Ark
+// tapscript leaves a node's single input always carries. It exists so this
+// package can validate congestion trees built on top of either
+// Liquid/Elements or Bitcoin L1 pools without branching on chain type
+// throughout the validator. See tree/elements and tree/bitcoin for the two
+// implementations.
+type ChainBackend interface {
+	// ParsePoolTx parses the pool transaction from its hex wire encoding.
+	ParsePoolTx(poolTxHex string) (PoolTx, error)
+
+	// ParseNodeTx decodes a congestion tree node's transaction from its
+	// base64 PSBT/PSET encoding.
+	ParseNodeTx(nodeTxBase64 string) (NodeTx, error)
+
+	// DecodeSweepScript reports whether script is the ASP's unilateral
+	// sweep leaf, and if so returns the ASP's x-only pubkey and the
+	// relative timelock, in seconds, encoded in it.
+	DecodeSweepScript(script []byte) (isSweep bool, aspPubKeyBytes []byte, seconds uint, err error)
+
+	// DecodeBranchScript reports whether script is a split/redeem branch
+	// leaf, and if so returns the left and right recipient x-only pubkeys
+	// (rightPubKeyBytes is nil for a single-child branch) and their
+	// amounts.
+	DecodeBranchScript(script []byte) (isBranch bool, leftPubKeyBytes, rightPubKeyBytes []byte, leftAmount, rightAmount uint64, err error)
+}
+
+// PoolTx is a decoded pool transaction, down to the detail
+// ValidateCongestionTree needs.
+type PoolTx interface {
+	// Txid is the pool transaction's txid.
+	Txid() string
+	// SharedOutputValue is the value of the pool transaction's shared
+	// output, the one the congestion tree's root node spends.
+	SharedOutputValue() (uint64, error)
+}
+
+// NodeTx is a decoded congestion tree node transaction.
+type NodeTx interface {
+	// Txid is the txid of the node's unsigned transaction.
+	Txid() (string, error)
+	// Input is the transaction's single input.
+	Input() (NodeTxInput, error)
+	// Outputs are every output of the transaction, in order, including
+	// the trailing fee output.
+	Outputs() ([]NodeTxOutput, error)
+}
+
+// NodeTxInput is a node transaction's single input.
+type NodeTxInput struct {
+	PreviousTxid string
+	// PreviousTxIndex is the output index of PreviousTxid this input
+	// spends, i.e. which of the parent node's (or pool tx's) outputs this
+	// node extends.
+	PreviousTxIndex int
+
+	// TapLeaves is the decoded set of tapscript leaves carried by the
+	// input's control blocks. A valid node transaction has exactly two:
+	// a sweep leaf and a branch leaf.
+	TapLeaves []TapLeaf
+}
+
+// TapLeaf is a single tapscript leaf, plus enough of its control block to
+// check it commits to a given parent output.
+type TapLeaf struct {
+	// InternalKeyBytes is the control block's x-only (32-byte) internal
+	// key.
+	InternalKeyBytes []byte
+	// OutputKeyBytes is the x-only (32-byte) taproot output key computed
+	// from the control block's root hash, i.e. what the parent output's
+	// witness program must equal for this leaf to be spendable from it.
+	OutputKeyBytes []byte
+	// Script is the raw leaf script, handed to DecodeSweepScript /
+	// DecodeBranchScript.
+	Script []byte
+}
+
+// NodeTxOutput is a single output of a node transaction.
+type NodeTxOutput struct {
+	// WitnessProgram is the output script with its witness-version and
+	// push-length prefix stripped (32 bytes for a taproot output).
+	WitnessProgram []byte
+	Value          uint64
+	// Asset identifies the output's asset on chains that have one
+	// (Elements). It's nil on Bitcoin, where every output is implicitly
+	// the chain's single native asset.
+	Asset []byte
+}
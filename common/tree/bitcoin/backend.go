@@ -0,0 +1,135 @@
+// Package bitcoin implements tree.ChainBackend for congestion trees built on
+// top of a plain Bitcoin L1 pool transaction, using btcd's psbt and wire
+// packages to parse the node transactions. Unlike the elements backend,
+// there's no asset to check: every output is implicitly the chain's single
+// native asset, so NodeTxOutput.Asset is always left nil.
+package bitcoin
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+
+	tree "github.com/ark-network/ark/common/tree"
+)
+
+const sharedOutputIndex = 0
+
+// backend is the unexported tree.ChainBackend implementation returned by
+// New.
+type backend struct{}
+
+// New returns the Bitcoin ChainBackend.
+func New() tree.ChainBackend {
+	return backend{}
+}
+
+func (backend) ParsePoolTx(poolTxHex string) (tree.PoolTx, error) {
+	raw, err := hex.DecodeString(poolTxHex)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := wire.NewMsgTx(wire.TxVersion)
+	if err := tx.Deserialize(bytes.NewReader(raw)); err != nil {
+		return nil, err
+	}
+	return poolTx{tx: tx}, nil
+}
+
+func (backend) ParseNodeTx(nodeTxBase64 string) (tree.NodeTx, error) {
+	p, err := psbt.NewFromRawBytes(strings.NewReader(nodeTxBase64), true)
+	if err != nil {
+		return nil, err
+	}
+	return nodeTx{pset: p}, nil
+}
+
+func (backend) DecodeSweepScript(script []byte) (bool, []byte, uint, error) {
+	return tree.DecodeSweepScript(script)
+}
+
+func (backend) DecodeBranchScript(script []byte) (bool, []byte, []byte, uint64, uint64, error) {
+	return tree.DecodeBranchScript(script)
+}
+
+type poolTx struct {
+	tx *wire.MsgTx
+}
+
+func (p poolTx) Txid() string {
+	return p.tx.TxHash().String()
+}
+
+func (p poolTx) SharedOutputValue() (uint64, error) {
+	if len(p.tx.TxOut) <= sharedOutputIndex {
+		return 0, fmt.Errorf("pool transaction has no output at index %d", sharedOutputIndex)
+	}
+	return uint64(p.tx.TxOut[sharedOutputIndex].Value), nil
+}
+
+type nodeTx struct {
+	pset *psbt.Packet
+}
+
+func (n nodeTx) Txid() (string, error) {
+	return n.pset.UnsignedTx.TxHash().String(), nil
+}
+
+func (n nodeTx) Input() (tree.NodeTxInput, error) {
+	if len(n.pset.Inputs) != 1 || len(n.pset.UnsignedTx.TxIn) != 1 {
+		return tree.NodeTxInput{}, fmt.Errorf("%w: got %d", tree.ErrNumberOfInputs, len(n.pset.UnsignedTx.TxIn))
+	}
+
+	in := n.pset.Inputs[0]
+	outpoint := n.pset.UnsignedTx.TxIn[0].PreviousOutPoint
+
+	leaves := make([]tree.TapLeaf, 0, len(in.TaprootLeafScript))
+	for _, tapLeaf := range in.TaprootLeafScript {
+		internalKey, err := schnorr.ParsePubKey(tapLeaf.ControlBlock[1:33])
+		if err != nil {
+			return tree.NodeTxInput{}, fmt.Errorf("invalid control block internal key: %w", err)
+		}
+
+		controlBlock, err := txscript.ParseControlBlock(tapLeaf.ControlBlock)
+		if err != nil {
+			return tree.NodeTxInput{}, fmt.Errorf("invalid control block: %w", err)
+		}
+
+		rootHash := controlBlock.RootHash(tapLeaf.Script)
+		outputKey := txscript.ComputeTaprootOutputKey(internalKey, rootHash)
+
+		leaves = append(leaves, tree.TapLeaf{
+			InternalKeyBytes: schnorr.SerializePubKey(internalKey),
+			OutputKeyBytes:   schnorr.SerializePubKey(outputKey),
+			Script:           tapLeaf.Script,
+		})
+	}
+
+	return tree.NodeTxInput{
+		PreviousTxid:    outpoint.Hash.String(),
+		PreviousTxIndex: int(outpoint.Index),
+		TapLeaves:       leaves,
+	}, nil
+}
+
+func (n nodeTx) Outputs() ([]tree.NodeTxOutput, error) {
+	outputs := make([]tree.NodeTxOutput, 0, len(n.pset.UnsignedTx.TxOut))
+	for _, out := range n.pset.UnsignedTx.TxOut {
+		var witnessProgram []byte
+		if len(out.PkScript) >= 2 {
+			witnessProgram = out.PkScript[2:]
+		}
+		outputs = append(outputs, tree.NodeTxOutput{
+			WitnessProgram: witnessProgram,
+			Value:          uint64(out.Value),
+		})
+	}
+	return outputs, nil
+}
@@ -0,0 +1,245 @@
+package bitcoin
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+
+	tree "github.com/ark-network/ark/common/tree"
+)
+
+func unsignedTx(outs ...*wire.TxOut) *wire.MsgTx {
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(&wire.TxIn{PreviousOutPoint: wire.OutPoint{Index: wire.MaxPrevOutIndex}})
+	for _, out := range outs {
+		tx.AddTxOut(out)
+	}
+	return tx
+}
+
+func serializeTx(t *testing.T, tx *wire.MsgTx) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := tx.Serialize(&buf); err != nil {
+		t.Fatalf("serializing tx: %s", err)
+	}
+	return hex.EncodeToString(buf.Bytes())
+}
+
+func TestParsePoolTx(t *testing.T) {
+	tx := unsignedTx(&wire.TxOut{Value: 150000, PkScript: []byte{0x51, 0x20}})
+	hexTx := serializeTx(t, tx)
+
+	parsed, err := New().ParsePoolTx(hexTx)
+	if err != nil {
+		t.Fatalf("parsing pool tx: %s", err)
+	}
+
+	if got, want := parsed.Txid(), tx.TxHash().String(); got != want {
+		t.Fatalf("txid: got %s, want %s", got, want)
+	}
+
+	value, err := parsed.SharedOutputValue()
+	if err != nil {
+		t.Fatalf("shared output value: %s", err)
+	}
+	if value != 150000 {
+		t.Fatalf("shared output value: got %d, want 150000", value)
+	}
+}
+
+func TestParsePoolTx_InvalidHex(t *testing.T) {
+	if _, err := New().ParsePoolTx("not-hex"); err == nil {
+		t.Fatal("expected an error for invalid hex")
+	}
+}
+
+func TestPoolTx_SharedOutputValue_NoSharedOutput(t *testing.T) {
+	tx := unsignedTx()
+	parsed, err := New().ParsePoolTx(serializeTx(t, tx))
+	if err != nil {
+		t.Fatalf("parsing pool tx: %s", err)
+	}
+
+	if _, err := parsed.SharedOutputValue(); err == nil {
+		t.Fatal("expected an error for a pool tx with no outputs")
+	}
+}
+
+// newUnsignedPacket builds a psbt.Packet around tx without any taproot leaf
+// scripts, enough to exercise Input's input-count check and Outputs, which
+// don't depend on any control block.
+func newUnsignedPacket(t *testing.T, tx *wire.MsgTx) *psbt.Packet {
+	t.Helper()
+	p, err := psbt.NewFromUnsignedTx(tx)
+	if err != nil {
+		t.Fatalf("building psbt packet: %s", err)
+	}
+	return p
+}
+
+func packetBase64(t *testing.T, p *psbt.Packet) string {
+	t.Helper()
+	b64, err := p.B64Encode()
+	if err != nil {
+		t.Fatalf("encoding psbt packet: %s", err)
+	}
+	return b64
+}
+
+func TestNodeTx_Input_WrongInputCount(t *testing.T) {
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxOut(&wire.TxOut{Value: 1000, PkScript: []byte{0x51, 0x20}})
+	p := newUnsignedPacket(t, tx)
+
+	node, err := New().ParseNodeTx(packetBase64(t, p))
+	if err != nil {
+		t.Fatalf("parsing node tx: %s", err)
+	}
+
+	if _, err := node.Input(); !errors.Is(err, tree.ErrNumberOfInputs) {
+		t.Fatalf("expected ErrNumberOfInputs, got %v", err)
+	}
+}
+
+func TestNodeTx_Outputs_StripsWitnessProgram(t *testing.T) {
+	witnessProgram := bytes.Repeat([]byte{0xab}, 32)
+	taprootScript := append([]byte{0x51, 0x20}, witnessProgram...)
+
+	tx := unsignedTx(
+		&wire.TxOut{Value: 1000, PkScript: taprootScript},
+		&wire.TxOut{Value: 0, PkScript: nil},
+	)
+	p := newUnsignedPacket(t, tx)
+
+	node, err := New().ParseNodeTx(packetBase64(t, p))
+	if err != nil {
+		t.Fatalf("parsing node tx: %s", err)
+	}
+
+	outputs, err := node.Outputs()
+	if err != nil {
+		t.Fatalf("reading outputs: %s", err)
+	}
+	if len(outputs) != 2 {
+		t.Fatalf("expected 2 outputs, got %d", len(outputs))
+	}
+	if !bytes.Equal(outputs[0].WitnessProgram, witnessProgram) {
+		t.Fatalf("witness program: got %x, want %x", outputs[0].WitnessProgram, witnessProgram)
+	}
+	if len(outputs[1].WitnessProgram) != 0 {
+		t.Fatalf("fee output should have an empty witness program, got %x", outputs[1].WitnessProgram)
+	}
+}
+
+func TestNodeTx_Txid(t *testing.T) {
+	tx := unsignedTx(&wire.TxOut{Value: 1000, PkScript: []byte{0x51, 0x20}})
+	p := newUnsignedPacket(t, tx)
+
+	node, err := New().ParseNodeTx(packetBase64(t, p))
+	if err != nil {
+		t.Fatalf("parsing node tx: %s", err)
+	}
+
+	got, err := node.Txid()
+	if err != nil {
+		t.Fatalf("reading txid: %s", err)
+	}
+	if want := tx.TxHash().String(); got != want {
+		t.Fatalf("txid: got %s, want %s", got, want)
+	}
+}
+
+func TestNodeTx_Input_PreviousOutpoint(t *testing.T) {
+	var prevHash chainhash.Hash
+	copy(prevHash[:], bytes.Repeat([]byte{0x11}, 32))
+
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(&wire.TxIn{PreviousOutPoint: wire.OutPoint{Hash: prevHash, Index: 1}})
+	tx.AddTxOut(&wire.TxOut{Value: 1000, PkScript: []byte{0x51, 0x20}})
+	p := newUnsignedPacket(t, tx)
+
+	node, err := New().ParseNodeTx(packetBase64(t, p))
+	if err != nil {
+		t.Fatalf("parsing node tx: %s", err)
+	}
+
+	input, err := node.Input()
+	if err != nil {
+		t.Fatalf("reading input: %s", err)
+	}
+	if input.PreviousTxid != prevHash.String() {
+		t.Fatalf("previous txid: got %s, want %s", input.PreviousTxid, prevHash.String())
+	}
+	if input.PreviousTxIndex != 1 {
+		t.Fatalf("previous tx index: got %d, want 1", input.PreviousTxIndex)
+	}
+}
+
+// Sweep and branch tapscripts are decoded by shared, chain-agnostic code
+// (common/tree.DecodeSweepScript/DecodeBranchScript); the backend just needs
+// to delegate to it without altering the result.
+func TestBackend_DecodeSweepScript_Delegates(t *testing.T) {
+	aspKey := bytes.Repeat([]byte{0x02}, 32)
+	seconds := uint64(604800)
+
+	script := make([]byte, 0, 44)
+	script = append(script, 32)
+	script = append(script, aspKey...)
+	script = append(script, 0xad)
+	script = append(script, 8)
+	secBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(secBytes, seconds)
+	script = append(script, secBytes...)
+	script = append(script, 0xb2)
+
+	isSweep, gotKey, gotSeconds, err := New().DecodeSweepScript(script)
+	if err != nil {
+		t.Fatalf("decoding sweep script: %s", err)
+	}
+	if !isSweep {
+		t.Fatal("expected script to decode as a sweep leaf")
+	}
+	if !bytes.Equal(gotKey, aspKey) {
+		t.Fatalf("asp key: got %x, want %x", gotKey, aspKey)
+	}
+	if uint64(gotSeconds) != seconds {
+		t.Fatalf("seconds: got %d, want %d", gotSeconds, seconds)
+	}
+}
+
+func TestBackend_DecodeBranchScript_Delegates(t *testing.T) {
+	leftKey := bytes.Repeat([]byte{0x03}, 32)
+	amount := uint64(1000)
+
+	record := make([]byte, 0, 42)
+	record = append(record, 32)
+	record = append(record, leftKey...)
+	record = append(record, 8)
+	amtBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(amtBytes, amount)
+	record = append(record, amtBytes...)
+
+	isBranch, gotLeft, gotRight, gotAmount, _, err := New().DecodeBranchScript(record)
+	if err != nil {
+		t.Fatalf("decoding branch script: %s", err)
+	}
+	if !isBranch {
+		t.Fatal("expected script to decode as a branch leaf")
+	}
+	if !bytes.Equal(gotLeft, leftKey) {
+		t.Fatalf("left key: got %x, want %x", gotLeft, leftKey)
+	}
+	if gotRight != nil {
+		t.Fatalf("expected no right key for a single-record branch script, got %x", gotRight)
+	}
+	if gotAmount != amount {
+		t.Fatalf("amount: got %d, want %d", gotAmount, amount)
+	}
+}
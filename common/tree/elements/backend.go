@@ -0,0 +1,120 @@
+// Package elements implements tree.ChainBackend for congestion trees built
+// on top of a Liquid/Elements pool transaction, using go-elements to parse
+// the PSET v2 node transactions and decred's secp256k1 for taproot key
+// arithmetic. This is the behavior ValidateCongestionTree had before it
+// became backend-parametric.
+package elements
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/vulpemventures/go-elements/elementsutil"
+	"github.com/vulpemventures/go-elements/psetv2"
+	"github.com/vulpemventures/go-elements/taproot"
+	"github.com/vulpemventures/go-elements/transaction"
+
+	tree "github.com/ark-network/ark/common/tree"
+)
+
+const sharedOutputIndex = 0
+
+// backend is the unexported tree.ChainBackend implementation returned by
+// New.
+type backend struct{}
+
+// New returns the Elements ChainBackend.
+func New() tree.ChainBackend {
+	return backend{}
+}
+
+func (backend) ParsePoolTx(poolTxHex string) (tree.PoolTx, error) {
+	tx, err := transaction.NewTxFromHex(poolTxHex)
+	if err != nil {
+		return nil, err
+	}
+	return poolTx{tx: tx}, nil
+}
+
+func (backend) ParseNodeTx(nodeTxBase64 string) (tree.NodeTx, error) {
+	pset, err := psetv2.NewPsetFromBase64(nodeTxBase64)
+	if err != nil {
+		return nil, err
+	}
+	return nodeTx{pset: pset}, nil
+}
+
+func (backend) DecodeSweepScript(script []byte) (bool, []byte, uint, error) {
+	return tree.DecodeSweepScript(script)
+}
+
+func (backend) DecodeBranchScript(script []byte) (bool, []byte, []byte, uint64, uint64, error) {
+	return tree.DecodeBranchScript(script)
+}
+
+type poolTx struct {
+	tx *transaction.Transaction
+}
+
+func (p poolTx) Txid() string {
+	return p.tx.TxHash().String()
+}
+
+func (p poolTx) SharedOutputValue() (uint64, error) {
+	return elementsutil.ValueFromBytes(p.tx.Outputs[sharedOutputIndex].Value)
+}
+
+type nodeTx struct {
+	pset *psetv2.Pset
+}
+
+func (n nodeTx) Txid() (string, error) {
+	utx, err := n.pset.UnsignedTx()
+	if err != nil {
+		return "", err
+	}
+	return utx.TxHash().String(), nil
+}
+
+func (n nodeTx) Input() (tree.NodeTxInput, error) {
+	if len(n.pset.Inputs) != 1 {
+		return tree.NodeTxInput{}, fmt.Errorf("%w: got %d", tree.ErrNumberOfInputs, len(n.pset.Inputs))
+	}
+	in := n.pset.Inputs[0]
+
+	leaves := make([]tree.TapLeaf, 0, len(in.TapLeafScript))
+	for _, tapLeaf := range in.TapLeafScript {
+		internalKey := tapLeaf.ControlBlock.InternalKey
+		rootHash := tapLeaf.ControlBlock.RootHash(tapLeaf.Script)
+		outputKey := taproot.ComputeTaprootOutputKey(internalKey, rootHash)
+
+		leaves = append(leaves, tree.TapLeaf{
+			InternalKeyBytes: schnorr.SerializePubKey(internalKey),
+			OutputKeyBytes:   schnorr.SerializePubKey(outputKey),
+			Script:           tapLeaf.Script,
+		})
+	}
+
+	return tree.NodeTxInput{
+		PreviousTxid:    chainhash.Hash(in.PreviousTxid).String(),
+		PreviousTxIndex: int(in.PreviousTxIndex),
+		TapLeaves:       leaves,
+	}, nil
+}
+
+func (n nodeTx) Outputs() ([]tree.NodeTxOutput, error) {
+	outputs := make([]tree.NodeTxOutput, 0, len(n.pset.Outputs))
+	for _, out := range n.pset.Outputs {
+		var witnessProgram []byte
+		if len(out.Script) >= 2 {
+			witnessProgram = out.Script[2:]
+		}
+		outputs = append(outputs, tree.NodeTxOutput{
+			WitnessProgram: witnessProgram,
+			Value:          out.Value,
+			Asset:          out.Asset,
+		})
+	}
+	return outputs, nil
+}
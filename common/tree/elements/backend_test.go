@@ -0,0 +1,78 @@
+package elements
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// DecodeSweepScript and DecodeBranchScript are the same chain-agnostic codec
+// the bitcoin backend delegates to (common/tree.DecodeSweepScript /
+// DecodeBranchScript); this only checks the Elements backend forwards to it
+// unchanged, the same way common/tree/bitcoin's equivalent tests do.
+//
+// ParsePoolTx/ParseNodeTx/Input/Outputs aren't covered here: exercising them
+// needs a real go-elements confidential transaction.Transaction or
+// psetv2.Pset built with that library's own constructors, which isn't
+// something worth hand-rolling byte-for-byte without the library on hand to
+// check the result against.
+func TestBackend_DecodeSweepScript_Delegates(t *testing.T) {
+	aspKey := bytes.Repeat([]byte{0x02}, 32)
+	seconds := uint64(604800)
+
+	script := make([]byte, 0, 44)
+	script = append(script, 32)
+	script = append(script, aspKey...)
+	script = append(script, 0xad)
+	script = append(script, 8)
+	secBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(secBytes, seconds)
+	script = append(script, secBytes...)
+	script = append(script, 0xb2)
+
+	isSweep, gotKey, gotSeconds, err := New().DecodeSweepScript(script)
+	if err != nil {
+		t.Fatalf("decoding sweep script: %s", err)
+	}
+	if !isSweep {
+		t.Fatal("expected script to decode as a sweep leaf")
+	}
+	if !bytes.Equal(gotKey, aspKey) {
+		t.Fatalf("asp key: got %x, want %x", gotKey, aspKey)
+	}
+	if uint64(gotSeconds) != seconds {
+		t.Fatalf("seconds: got %d, want %d", gotSeconds, seconds)
+	}
+}
+
+func TestBackend_DecodeBranchScript_Delegates(t *testing.T) {
+	leftKey := bytes.Repeat([]byte{0x03}, 32)
+	rightKey := bytes.Repeat([]byte{0x04}, 32)
+	leftAmount, rightAmount := uint64(1000), uint64(2000)
+
+	record := func(key []byte, amount uint64) []byte {
+		r := make([]byte, 0, 42)
+		r = append(r, 32)
+		r = append(r, key...)
+		r = append(r, 8)
+		amtBytes := make([]byte, 8)
+		binary.LittleEndian.PutUint64(amtBytes, amount)
+		return append(r, amtBytes...)
+	}
+
+	script := append(record(leftKey, leftAmount), record(rightKey, rightAmount)...)
+
+	isBranch, gotLeft, gotRight, gotLeftAmount, gotRightAmount, err := New().DecodeBranchScript(script)
+	if err != nil {
+		t.Fatalf("decoding branch script: %s", err)
+	}
+	if !isBranch {
+		t.Fatal("expected script to decode as a branch leaf")
+	}
+	if !bytes.Equal(gotLeft, leftKey) || !bytes.Equal(gotRight, rightKey) {
+		t.Fatalf("keys: got left=%x right=%x, want left=%x right=%x", gotLeft, gotRight, leftKey, rightKey)
+	}
+	if gotLeftAmount != leftAmount || gotRightAmount != rightAmount {
+		t.Fatalf("amounts: got left=%d right=%d, want left=%d right=%d", gotLeftAmount, gotRightAmount, leftAmount, rightAmount)
+	}
+}
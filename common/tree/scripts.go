@@ -0,0 +1,102 @@
+package tree
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Shared pushdata layout for the two Ark tapscript leaves, used by every
+// ChainBackend implementation so the wire format stays identical across
+// chains even though each backend parses it with its own taproot/script
+// library. A single push is encoded as a one-byte length prefix (like a
+// direct Script push of <=75 bytes) followed by that many bytes.
+const (
+	opCheckSigVerify      = 0xad
+	opCheckSequenceVerify = 0xb2
+	pubKeyLen             = 32
+	secondsFieldLen       = 8
+	amountFieldLen        = 8
+	sweepScriptLen        = 1 + pubKeyLen + 1 + 1 + secondsFieldLen + 1
+	singleBranchScriptLen = 1 + pubKeyLen + 1 + amountFieldLen
+	doubleBranchScriptLen = 2 * singleBranchScriptLen
+)
+
+// DecodeSweepScript reports whether script is an ASP sweep leaf of the form
+// <aspPubKey> OP_CHECKSIGVERIFY <seconds> OP_CHECKSEQUENCEVERIFY, and if so
+// returns the embedded ASP pubkey and relative timelock in seconds.
+func DecodeSweepScript(script []byte) (isSweep bool, aspPubKeyBytes []byte, seconds uint, err error) {
+	if len(script) != sweepScriptLen {
+		return false, nil, 0, nil
+	}
+
+	if script[0] != pubKeyLen {
+		return false, nil, 0, nil
+	}
+	pubKey := script[1 : 1+pubKeyLen]
+
+	offset := 1 + pubKeyLen
+	if script[offset] != opCheckSigVerify {
+		return false, nil, 0, nil
+	}
+	offset++
+
+	if script[offset] != secondsFieldLen {
+		return false, nil, 0, nil
+	}
+	offset++
+	seconds = uint(binary.LittleEndian.Uint64(script[offset : offset+secondsFieldLen]))
+	offset += secondsFieldLen
+
+	if script[offset] != opCheckSequenceVerify {
+		return false, nil, 0, nil
+	}
+
+	return true, pubKey, seconds, nil
+}
+
+// DecodeBranchScript reports whether script is a split/redeem branch leaf
+// carrying one or two <pubkey><amount> records, and if so returns the
+// left (and, if present, right) recipient pubkey and amount.
+func DecodeBranchScript(script []byte) (isBranch bool, leftPubKeyBytes, rightPubKeyBytes []byte, leftAmount, rightAmount uint64, err error) {
+	switch len(script) {
+	case singleBranchScriptLen:
+		leftPubKeyBytes, leftAmount, err = decodeBranchRecord(script)
+		if err != nil {
+			return false, nil, nil, 0, 0, err
+		}
+		return true, leftPubKeyBytes, nil, leftAmount, 0, nil
+
+	case doubleBranchScriptLen:
+		left := script[:singleBranchScriptLen]
+		right := script[singleBranchScriptLen:]
+
+		leftPubKeyBytes, leftAmount, err = decodeBranchRecord(left)
+		if err != nil {
+			return false, nil, nil, 0, 0, err
+		}
+		rightPubKeyBytes, rightAmount, err = decodeBranchRecord(right)
+		if err != nil {
+			return false, nil, nil, 0, 0, err
+		}
+		return true, leftPubKeyBytes, rightPubKeyBytes, leftAmount, rightAmount, nil
+
+	default:
+		return false, nil, nil, 0, 0, nil
+	}
+}
+
+func decodeBranchRecord(record []byte) ([]byte, uint64, error) {
+	if record[0] != pubKeyLen {
+		return nil, 0, fmt.Errorf("invalid branch record: expected a %d-byte pubkey push", pubKeyLen)
+	}
+	pubKey := record[1 : 1+pubKeyLen]
+
+	offset := 1 + pubKeyLen
+	if record[offset] != amountFieldLen {
+		return nil, 0, fmt.Errorf("invalid branch record: expected an %d-byte amount push", amountFieldLen)
+	}
+	offset++
+	amount := binary.LittleEndian.Uint64(record[offset : offset+amountFieldLen])
+
+	return pubKey, amount, nil
+}
@@ -0,0 +1,178 @@
+package tree
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TestVectorFormatVersion is the current version of the on-disk test vector
+// schema. Bump it whenever a field is added, removed or reinterpreted so
+// that loaders (in this repo or in other Ark implementations) can reject or
+// migrate vectors written against an older version instead of silently
+// misreading them.
+const TestVectorFormatVersion = 1
+
+// sentinelsByName maps the string name of every Err* sentinel declared in
+// validation.go to the sentinel itself, so a test vector can reference an
+// expected error by name instead of relying on Go identifiers.
+var sentinelsByName = map[string]error{
+	"ErrInvalidPoolTransaction":   ErrInvalidPoolTransaction,
+	"ErrEmptyTree":                ErrEmptyTree,
+	"ErrInvalidRootLevel":         ErrInvalidRootLevel,
+	"ErrNoLeaves":                 ErrNoLeaves,
+	"ErrNodeTransactionEmpty":     ErrNodeTransactionEmpty,
+	"ErrNodeTxidEmpty":            ErrNodeTxidEmpty,
+	"ErrNodeParentTxidEmpty":      ErrNodeParentTxidEmpty,
+	"ErrNodeTxidDifferent":        ErrNodeTxidDifferent,
+	"ErrNumberOfInputs":           ErrNumberOfInputs,
+	"ErrNumberOfOutputs":          ErrNumberOfOutputs,
+	"ErrParentTxidInput":          ErrParentTxidInput,
+	"ErrNumberOfChildren":         ErrNumberOfChildren,
+	"ErrLeafChildren":             ErrLeafChildren,
+	"ErrInvalidChildTxid":         ErrInvalidChildTxid,
+	"ErrNumberOfTapscripts":       ErrNumberOfTapscripts,
+	"ErrInternalKey":              ErrInternalKey,
+	"ErrInvalidTaprootScript":     ErrInvalidTaprootScript,
+	"ErrInvalidLeafTaprootScript": ErrInvalidLeafTaprootScript,
+	"ErrInvalidAmount":            ErrInvalidAmount,
+	"ErrInvalidAsset":             ErrInvalidAsset,
+	"ErrInvalidSweepSequence":     ErrInvalidSweepSequence,
+	"ErrInvalidASP":               ErrInvalidASP,
+	"ErrMissingFeeOutput":         ErrMissingFeeOutput,
+	"ErrInvalidLeftOutput":        ErrInvalidLeftOutput,
+	"ErrInvalidRightOutput":       ErrInvalidRightOutput,
+	"ErrMissingSweepTapscript":    ErrMissingSweepTapscript,
+	"ErrMissingBranchTapscript":   ErrMissingBranchTapscript,
+	"ErrInvalidLeaf":              ErrInvalidLeaf,
+	"ErrWrongPoolTxID":            ErrWrongPoolTxID,
+}
+
+// SentinelByName returns the Err* sentinel registered under name, so callers
+// outside this package (e.g. cmd/ark-vectorgen) can label generated vectors
+// without duplicating the error table.
+func SentinelByName(name string) (error, bool) {
+	err, ok := sentinelsByName[name]
+	return err, ok
+}
+
+// TestVector is a single hand-crafted or generated case exercised against
+// ValidateCongestionTree. A vector with an empty ExpectedErr describes a
+// tree that must validate successfully; otherwise ExpectedErr must name one
+// of the Err* sentinels in sentinelsByName and the loader fails fast on
+// typos or renamed errors.
+type TestVector struct {
+	Version              int            `json:"version" yaml:"version"`
+	Description          string         `json:"description" yaml:"description"`
+	Tags                 []string       `json:"tags" yaml:"tags"`
+	PoolTxHex            string         `json:"poolTxHex" yaml:"poolTxHex"`
+	ASPPublicKeyHex      string         `json:"aspPublicKeyHex" yaml:"aspPublicKeyHex"`
+	RoundLifetimeSeconds uint           `json:"roundLifetimeSeconds" yaml:"roundLifetimeSeconds"`
+	Tree                 CongestionTree `json:"tree" yaml:"tree"`
+	ExpectedErr          string         `json:"expectedErr" yaml:"expectedErr"`
+
+	// source is the file the vector was loaded from, kept for error messages.
+	source string
+}
+
+// WantErr resolves ExpectedErr to its sentinel. ok is false when the vector
+// describes a successful validation.
+func (v TestVector) WantErr() (err error, ok bool) {
+	if v.ExpectedErr == "" {
+		return nil, false
+	}
+	sentinel, known := sentinelsByName[v.ExpectedErr]
+	return sentinel, known
+}
+
+// ASPPublicKey parses ASPPublicKeyHex into the 32-byte x-only key expected
+// by ValidateCongestionTree.
+func (v TestVector) ASPPublicKey() ([]byte, error) {
+	raw, err := hex.DecodeString(v.ASPPublicKeyHex)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) != 32 {
+		return nil, fmt.Errorf("expected a 32-byte x-only public key, got %d bytes", len(raw))
+	}
+	return raw, nil
+}
+
+// LoadTestVectors reads every *.json, *.yaml and *.yml file under dir and
+// decodes each one into a TestVector, rejecting any vector whose Version
+// doesn't match TestVectorFormatVersion or whose ExpectedErr doesn't name a
+// known sentinel. Files are read in lexical order so a failing corpus
+// reports deterministically.
+func LoadTestVectors(dir string) ([]TestVector, error) {
+	var paths []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".json", ".yaml", ".yml":
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walking test vector corpus %s: %w", dir, err)
+	}
+
+	vectors := make([]TestVector, 0, len(paths))
+	for _, path := range paths {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading test vector %s: %w", path, err)
+		}
+
+		var v TestVector
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".json":
+			err = json.Unmarshal(raw, &v)
+		default:
+			err = yaml.Unmarshal(raw, &v)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decoding test vector %s: %w", path, err)
+		}
+		v.source = path
+
+		if v.Version != TestVectorFormatVersion {
+			return nil, fmt.Errorf("test vector %s: unsupported format version %d, want %d", path, v.Version, TestVectorFormatVersion)
+		}
+		if v.ExpectedErr != "" {
+			if _, ok := sentinelsByName[v.ExpectedErr]; !ok {
+				return nil, fmt.Errorf("test vector %s: unknown expectedErr %q", path, v.ExpectedErr)
+			}
+		}
+
+		vectors = append(vectors, v)
+	}
+
+	return vectors, nil
+}
+
+// HasTag reports whether the vector is labeled with tag, for CI jobs that
+// only want to run a subset of the corpus (e.g. "sweep-script").
+func (v TestVector) HasTag(tag string) bool {
+	for _, t := range v.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Source is the file the vector was loaded from, for diagnostics.
+func (v TestVector) Source() string {
+	return v.source
+}
@@ -0,0 +1,231 @@
+package tree
+
+import (
+	"encoding/binary"
+	"fmt"
+	"testing"
+)
+
+func BenchmarkValidateCongestionTree_2_8(b *testing.B)  { runValidateBenchmark(b, 1<<8) }
+func BenchmarkValidateCongestionTree_2_12(b *testing.B) { runValidateBenchmark(b, 1<<12) }
+func BenchmarkValidateCongestionTree_2_16(b *testing.B) { runValidateBenchmark(b, 1<<16) }
+
+func runValidateBenchmark(b *testing.B, numLeaves int) {
+	backend, congestionTree, poolTxKey, aspKey, lifetime := buildBenchTree(numLeaves)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := ValidateCongestionTree(backend, congestionTree, poolTxKey, aspKey, lifetime); err != nil {
+			b.Fatalf("tree with %d leaves must validate successfully: %s", numLeaves, err)
+		}
+	}
+}
+
+const (
+	benchVtxoAmount      = uint64(1000)
+	benchLifetimeSeconds = uint(604800)
+	benchPoolTxKey       = "pool"
+)
+
+// benchNode is what buildBenchTree knows about a single tree node while
+// it's still assembling the level below it: the txid, the output key its
+// parent's output must commit to, and the total value its own outputs add
+// up to (what its parent's output toward it must equal).
+type benchNode struct {
+	txid      string
+	outputKey []byte
+	outputSum uint64
+}
+
+// buildBenchTree builds a perfect binary congestion tree of numLeaves
+// leaves entirely in process, against a fake ChainBackend that looks
+// transactions up from a map instead of parsing a wire encoding. A tree
+// this wide takes real taproot signatures to build against a real chain
+// backend, which isn't something a benchmark tracking the validator's own
+// node-walking and caching overhead should be paying for on every run;
+// benchBackend still round-trips every node through the same
+// DecodeSweepScript/DecodeBranchScript codec a real backend does — only
+// the transaction parsing itself is stubbed out.
+func buildBenchTree(numLeaves int) (backend ChainBackend, congestionTree CongestionTree, poolTxKey string, aspKey []byte, lifetime uint) {
+	aspKey = unspendableKeyBytes()
+	lifetime = benchLifetimeSeconds
+	poolTxKey = benchPoolTxKey
+
+	depth := 0
+	for 1<<depth < numLeaves {
+		depth++
+	}
+
+	fake := &benchBackend{nodes: make(map[string]benchNodeTx, 2*numLeaves)}
+	congestionTree = make(CongestionTree, depth+1)
+
+	level := make([]benchNode, numLeaves)
+	congestionTree[depth] = make([]Node, numLeaves)
+	for i := range level {
+		txid := benchTxid(depth, i)
+		outputKey := benchKey(depth, i)
+		parentTxid := benchParentTxidOf(depth, i)
+
+		level[i] = benchNode{txid: txid, outputKey: outputKey, outputSum: benchVtxoAmount}
+		fake.nodes[txid] = benchNodeTx{
+			txid: txid,
+			input: NodeTxInput{
+				PreviousTxid: parentTxid,
+				TapLeaves: []TapLeaf{
+					benchSweepLeaf(outputKey, aspKey, lifetime),
+					benchBranchLeaf(outputKey, outputKey, benchVtxoAmount, nil, 0),
+				},
+			},
+			outputs: []NodeTxOutput{
+				{WitnessProgram: outputKey, Value: benchVtxoAmount},
+				{WitnessProgram: nil, Value: 0},
+			},
+		}
+		congestionTree[depth][i] = Node{Tx: txid, Txid: txid, ParentTxid: parentTxid, Leaf: true}
+	}
+
+	for lvl := depth - 1; lvl >= 0; lvl-- {
+		width := len(level) / 2
+		next := make([]benchNode, width)
+		congestionTree[lvl] = make([]Node, width)
+
+		for i := 0; i < width; i++ {
+			left, right := level[2*i], level[2*i+1]
+			txid := benchTxid(lvl, i)
+			outputKey := benchKey(lvl, i)
+			parentTxid := benchParentTxidOf(lvl, i)
+			outputSum := left.outputSum + right.outputSum
+
+			fake.nodes[txid] = benchNodeTx{
+				txid: txid,
+				input: NodeTxInput{
+					PreviousTxid: parentTxid,
+					TapLeaves: []TapLeaf{
+						benchSweepLeaf(outputKey, aspKey, lifetime),
+						benchBranchLeaf(outputKey, left.outputKey, left.outputSum, right.outputKey, right.outputSum),
+					},
+				},
+				outputs: []NodeTxOutput{
+					{WitnessProgram: left.outputKey, Value: left.outputSum},
+					{WitnessProgram: right.outputKey, Value: right.outputSum},
+					{WitnessProgram: nil, Value: 0},
+				},
+			}
+
+			next[i] = benchNode{txid: txid, outputKey: outputKey, outputSum: outputSum}
+			congestionTree[lvl][i] = Node{Tx: txid, Txid: txid, ParentTxid: parentTxid, Leaf: false}
+		}
+
+		level = next
+	}
+
+	fake.pool = benchPoolTx{txid: benchPoolTxKey, sharedValue: level[0].outputSum}
+
+	return fake, congestionTree, poolTxKey, aspKey, lifetime
+}
+
+// benchParentTxidOf returns the txid buildBenchTree gives the parent of the
+// node at (level, index), or benchPoolTxKey for the root.
+func benchParentTxidOf(level, index int) string {
+	if level == 0 {
+		return benchPoolTxKey
+	}
+	return benchTxid(level-1, index/2)
+}
+
+func benchTxid(level, index int) string {
+	return fmt.Sprintf("node-%d-%d", level, index)
+}
+
+func benchKey(level, index int) []byte {
+	key := make([]byte, pubKeyLen)
+	binary.BigEndian.PutUint32(key[0:4], uint32(level))
+	binary.BigEndian.PutUint32(key[4:8], uint32(index))
+	return key
+}
+
+func benchSweepLeaf(outputKey, aspKey []byte, seconds uint) TapLeaf {
+	script := make([]byte, 0, sweepScriptLen)
+	script = append(script, pubKeyLen)
+	script = append(script, aspKey...)
+	script = append(script, opCheckSigVerify)
+	script = append(script, secondsFieldLen)
+	secBytes := make([]byte, secondsFieldLen)
+	binary.LittleEndian.PutUint64(secBytes, uint64(seconds))
+	script = append(script, secBytes...)
+	script = append(script, opCheckSequenceVerify)
+
+	return TapLeaf{InternalKeyBytes: unspendableKeyBytes(), OutputKeyBytes: outputKey, Script: script}
+}
+
+func benchBranchLeaf(outputKey, leftKey []byte, leftAmount uint64, rightKey []byte, rightAmount uint64) TapLeaf {
+	script := make([]byte, 0, doubleBranchScriptLen)
+	script = append(script, benchBranchRecord(leftKey, leftAmount)...)
+	if rightKey != nil {
+		script = append(script, benchBranchRecord(rightKey, rightAmount)...)
+	}
+
+	return TapLeaf{InternalKeyBytes: unspendableKeyBytes(), OutputKeyBytes: outputKey, Script: script}
+}
+
+func benchBranchRecord(key []byte, amount uint64) []byte {
+	record := make([]byte, 0, singleBranchScriptLen)
+	record = append(record, pubKeyLen)
+	record = append(record, key...)
+	record = append(record, amountFieldLen)
+	amtBytes := make([]byte, amountFieldLen)
+	binary.LittleEndian.PutUint64(amtBytes, amount)
+	record = append(record, amtBytes...)
+	return record
+}
+
+// benchBackend is a ChainBackend that looks transactions up from an
+// in-memory map instead of parsing a wire encoding, so buildBenchTree
+// doesn't need a real PSET/taproot signer to produce a tree big enough to
+// benchmark.
+type benchBackend struct {
+	pool  benchPoolTx
+	nodes map[string]benchNodeTx
+}
+
+func (b *benchBackend) ParsePoolTx(key string) (PoolTx, error) {
+	if key != b.pool.txid {
+		return nil, fmt.Errorf("unknown pool tx key %q", key)
+	}
+	return b.pool, nil
+}
+
+func (b *benchBackend) ParseNodeTx(key string) (NodeTx, error) {
+	n, ok := b.nodes[key]
+	if !ok {
+		return nil, fmt.Errorf("unknown node tx key %q", key)
+	}
+	return n, nil
+}
+
+func (b *benchBackend) DecodeSweepScript(script []byte) (bool, []byte, uint, error) {
+	return DecodeSweepScript(script)
+}
+
+func (b *benchBackend) DecodeBranchScript(script []byte) (bool, []byte, []byte, uint64, uint64, error) {
+	return DecodeBranchScript(script)
+}
+
+type benchPoolTx struct {
+	txid        string
+	sharedValue uint64
+}
+
+func (p benchPoolTx) Txid() string                       { return p.txid }
+func (p benchPoolTx) SharedOutputValue() (uint64, error) { return p.sharedValue, nil }
+
+type benchNodeTx struct {
+	txid    string
+	input   NodeTxInput
+	outputs []NodeTxOutput
+}
+
+func (n benchNodeTx) Txid() (string, error)            { return n.txid, nil }
+func (n benchNodeTx) Input() (NodeTxInput, error)      { return n.input, nil }
+func (n benchNodeTx) Outputs() ([]NodeTxOutput, error) { return n.outputs, nil }
@@ -0,0 +1,227 @@
+package tree
+
+import (
+	"errors"
+	"testing"
+)
+
+// smallBenchTree builds the 2-leaf tree buildBenchTree produces (a single
+// root spending the pool tx and two leaves spending the root), and returns
+// the concrete *benchBackend alongside it so tests can reach into and
+// corrupt individual nodes.
+func smallBenchTree(t *testing.T) (fake *benchBackend, congestionTree CongestionTree, poolTxKey string, aspKey []byte, lifetime uint) {
+	t.Helper()
+
+	backend, tree, poolKey, asp, secs := buildBenchTree(2)
+	fake, ok := backend.(*benchBackend)
+	if !ok {
+		t.Fatalf("buildBenchTree returned %T, want *benchBackend", backend)
+	}
+	return fake, tree, poolKey, asp, secs
+}
+
+func TestValidateCongestionTree_BenchTreeIsValid(t *testing.T) {
+	fake, tree, poolTxKey, aspKey, lifetime := smallBenchTree(t)
+
+	if err := ValidateCongestionTree(fake, tree, poolTxKey, aspKey, lifetime); err != nil {
+		t.Fatalf("expected a freshly built tree to validate, got %s", err)
+	}
+}
+
+// TestValidateCongestionTree_RootMissingBranchTapscript exercises the
+// per-node tapleaf-shape checks (internal key, exactly one sweep and one
+// branch leaf) against the tree's root, not just its children: the
+// original sequential validator only ran these while walking a node's
+// children, which the root never is, so a root whose input carries two
+// sweep-shaped leaves instead of a sweep and a branch now fails the same
+// way a non-root node with the same defect would.
+func TestValidateCongestionTree_RootMissingBranchTapscript(t *testing.T) {
+	fake, tree, poolTxKey, aspKey, lifetime := smallBenchTree(t)
+
+	root := fake.nodes["node-0-0"]
+	outputKey := root.input.TapLeaves[0].OutputKeyBytes
+	root.input.TapLeaves = []TapLeaf{
+		benchSweepLeaf(outputKey, aspKey, lifetime),
+		benchSweepLeaf(outputKey, aspKey, lifetime),
+	}
+	fake.nodes["node-0-0"] = root
+
+	if err := ValidateCongestionTree(fake, tree, poolTxKey, aspKey, lifetime); !errors.Is(err, ErrMissingBranchTapscript) {
+		t.Fatalf("expected ErrMissingBranchTapscript, got %v", err)
+	}
+}
+
+// mutateRootLeftAmount rebuilds the root's branch tapleaf and left output so
+// both still agree with each other (so the root's own self-checks still
+// pass) but now claim newAmount for the left child instead of the amount
+// the left child's own outputs actually sum to. That is caught only by the
+// amount-conservation checks: the root's outputs no longer add up to the
+// pool output, and the left child's outputs no longer add up to its parent
+// output.
+func mutateRootLeftAmount(fake *benchBackend, rootTxid string, newAmount uint64, aspKey []byte, lifetime uint) {
+	root := fake.nodes[rootTxid]
+	outputKey := root.input.TapLeaves[0].OutputKeyBytes
+	leftKey := root.outputs[0].WitnessProgram
+	rightKey := root.outputs[1].WitnessProgram
+	rightAmount := root.outputs[1].Value
+
+	root.input.TapLeaves = []TapLeaf{
+		benchSweepLeaf(outputKey, aspKey, lifetime),
+		benchBranchLeaf(outputKey, leftKey, newAmount, rightKey, rightAmount),
+	}
+	root.outputs[0].Value = newAmount
+	fake.nodes[rootTxid] = root
+}
+
+func TestValidationFlags_BFSkipAmounts(t *testing.T) {
+	fake, tree, poolTxKey, aspKey, lifetime := smallBenchTree(t)
+	mutateRootLeftAmount(fake, "node-0-0", 1, aspKey, lifetime)
+
+	if err := ValidateCongestionTree(fake, tree, poolTxKey, aspKey, lifetime); !errors.Is(err, ErrInvalidAmount) {
+		t.Fatalf("expected ErrInvalidAmount, got %v", err)
+	}
+
+	err := ValidateCongestionTreeWithFlags(fake, tree, poolTxKey, aspKey, lifetime, BFSkipAmounts)
+	if err != nil {
+		t.Fatalf("BFSkipAmounts should skip the broken amount check, got %s", err)
+	}
+}
+
+// mutateLeafOutputKey rewrites a leaf's tapleaf control blocks so they
+// commit to an output key other than its own, breaking the check that a
+// leaf's tapscripts actually match the witness program its parent sent it.
+func mutateLeafOutputKey(fake *benchBackend, leafTxid string, wrongKey []byte) {
+	leaf := fake.nodes[leafTxid]
+	leaves := append([]TapLeaf(nil), leaf.input.TapLeaves...)
+	for i := range leaves {
+		leaves[i].OutputKeyBytes = wrongKey
+	}
+	leaf.input.TapLeaves = leaves
+	fake.nodes[leafTxid] = leaf
+}
+
+func TestValidationFlags_BFSkipTaprootScripts(t *testing.T) {
+	fake, tree, poolTxKey, aspKey, lifetime := smallBenchTree(t)
+	mutateLeafOutputKey(fake, "node-1-0", benchKey(9, 9))
+
+	if err := ValidateCongestionTree(fake, tree, poolTxKey, aspKey, lifetime); !errors.Is(err, ErrInvalidTaprootScript) {
+		t.Fatalf("expected ErrInvalidTaprootScript, got %v", err)
+	}
+
+	err := ValidateCongestionTreeWithFlags(fake, tree, poolTxKey, aspKey, lifetime, BFSkipTaprootScripts)
+	if err != nil {
+		t.Fatalf("BFSkipTaprootScripts should skip the broken control block check, got %s", err)
+	}
+}
+
+// mutateLeafAsset rewrites a leaf's outputs to carry asset, breaking its
+// asset agreement with whatever its parent recorded for it (nil, by
+// construction in buildBenchTree), without touching any amount.
+func mutateLeafAsset(fake *benchBackend, leafTxid string, asset []byte) {
+	leaf := fake.nodes[leafTxid]
+	outputs := append([]NodeTxOutput(nil), leaf.outputs...)
+	for i := range outputs {
+		outputs[i].Asset = asset
+	}
+	leaf.outputs = outputs
+	fake.nodes[leafTxid] = leaf
+}
+
+func TestValidationFlags_BFSkipAssetChecks(t *testing.T) {
+	fake, tree, poolTxKey, aspKey, lifetime := smallBenchTree(t)
+	mutateLeafAsset(fake, "node-1-0", []byte("wrong-asset"))
+
+	if err := ValidateCongestionTree(fake, tree, poolTxKey, aspKey, lifetime); !errors.Is(err, ErrInvalidAsset) {
+		t.Fatalf("expected ErrInvalidAsset, got %v", err)
+	}
+
+	// BFSkipAmounts must not also suppress the asset check: they're
+	// distinct properties and a caller may want one skipped without the
+	// other.
+	err := ValidateCongestionTreeWithFlags(fake, tree, poolTxKey, aspKey, lifetime, BFSkipAmounts)
+	if !errors.Is(err, ErrInvalidAsset) {
+		t.Fatalf("BFSkipAmounts alone should not skip the asset check, got %v", err)
+	}
+
+	err = ValidateCongestionTreeWithFlags(fake, tree, poolTxKey, aspKey, lifetime, BFSkipAssetChecks)
+	if err != nil {
+		t.Fatalf("BFSkipAssetChecks should skip the broken asset check, got %s", err)
+	}
+}
+
+func TestValidationFlags_BFAssumeRootValid(t *testing.T) {
+	fake, tree, _, aspKey, lifetime := smallBenchTree(t)
+	// Swap in a parseable pool tx whose txid the root's input doesn't spend.
+	fake.pool = benchPoolTx{txid: "not-the-pool", sharedValue: fake.pool.sharedValue}
+	wrongPoolTxKey := fake.pool.txid
+
+	if err := ValidateCongestionTree(fake, tree, wrongPoolTxKey, aspKey, lifetime); !errors.Is(err, ErrWrongPoolTxID) {
+		t.Fatalf("expected ErrWrongPoolTxID, got %v", err)
+	}
+
+	err := ValidateCongestionTreeWithFlags(fake, tree, wrongPoolTxKey, aspKey, lifetime, BFAssumeRootValid)
+	if err != nil {
+		t.Fatalf("BFAssumeRootValid should skip linking the root to the pool tx, got %s", err)
+	}
+}
+
+func TestValidationFlags_BFLeavesOnly(t *testing.T) {
+	fake, tree, poolTxKey, aspKey, lifetime := smallBenchTree(t)
+
+	root := fake.nodes["node-0-0"]
+	leaves := append([]TapLeaf(nil), root.input.TapLeaves...)
+	leaves[0].InternalKeyBytes = benchKey(9, 9)
+	root.input.TapLeaves = leaves
+	fake.nodes["node-0-0"] = root
+
+	if err := ValidateCongestionTree(fake, tree, poolTxKey, aspKey, lifetime); !errors.Is(err, ErrInternalKey) {
+		t.Fatalf("expected ErrInternalKey, got %v", err)
+	}
+
+	err := ValidateCongestionTreeWithFlags(fake, tree, poolTxKey, aspKey, lifetime, BFLeavesOnly)
+	if err != nil {
+		t.Fatalf("BFLeavesOnly should skip validating the non-leaf root, got %s", err)
+	}
+}
+
+func TestValidateSubtree(t *testing.T) {
+	fake, tree, _, aspKey, lifetime := smallBenchTree(t)
+
+	if err := ValidateSubtree(fake, tree, "node-0-0", aspKey, lifetime); err != nil {
+		t.Fatalf("expected the whole tree to validate as its own subtree, got %s", err)
+	}
+
+	mutateLeafOutputKey(fake, "node-1-0", benchKey(9, 9))
+	if err := ValidateSubtree(fake, tree, "node-0-0", aspKey, lifetime); !errors.Is(err, ErrInvalidTaprootScript) {
+		t.Fatalf("expected ErrInvalidTaprootScript, got %v", err)
+	}
+
+	// node-1-0 is also caught when it's the subtree root itself, even
+	// though its real parent (node-0-0) isn't part of the subtree
+	// validateNodes ever sees: ValidateSubtree must still check it against
+	// node-0-0 as found in the full tree.
+	if err := ValidateSubtree(fake, tree, "node-1-0", aspKey, lifetime); !errors.Is(err, ErrInvalidTaprootScript) {
+		t.Fatalf("expected ErrInvalidTaprootScript against the real parent, got %v", err)
+	}
+
+	if err := ValidateSubtree(fake, tree, "no-such-node", aspKey, lifetime); !errors.Is(err, ErrInvalidChildTxid) {
+		t.Fatalf("expected ErrInvalidChildTxid for an unknown root, got %v", err)
+	}
+}
+
+func TestValidateNode(t *testing.T) {
+	fake, tree, _, aspKey, lifetime := smallBenchTree(t)
+
+	root := tree[0][0]
+	leftLeaf := tree[1][0]
+
+	if err := ValidateNode(fake, leftLeaf, root, aspKey, lifetime); err != nil {
+		t.Fatalf("expected the left leaf to validate against its parent, got %s", err)
+	}
+
+	wrongParent := root
+	wrongParent.Txid = "not-the-real-parent"
+	if err := ValidateNode(fake, leftLeaf, wrongParent, aspKey, lifetime); !errors.Is(err, ErrParentTxidInput) {
+		t.Fatalf("expected ErrParentTxidInput, got %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package tree
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ark-network/ark/common/tree/elements"
+)
+
+// TestValidateCongestionTreeVectors drives ValidateCongestionTree against
+// every vector checked into testvectors/, asserting the exact Err* sentinel
+// named by the vector (or no error at all) rather than just "any error".
+func TestValidateCongestionTreeVectors(t *testing.T) {
+	vectors, err := LoadTestVectors("testvectors")
+	if err != nil {
+		t.Fatalf("loading test vector corpus: %s", err)
+	}
+	if len(vectors) == 0 {
+		t.Fatal("test vector corpus is empty")
+	}
+
+	for _, v := range vectors {
+		v := v
+		t.Run(v.Description, func(t *testing.T) {
+			aspPubKey, err := v.ASPPublicKey()
+			if err != nil {
+				t.Fatalf("%s: invalid aspPublicKeyHex: %s", v.Source(), err)
+			}
+
+			gotErr := ValidateCongestionTree(elements.New(), v.Tree, v.PoolTxHex, aspPubKey, v.RoundLifetimeSeconds)
+
+			wantErr, wantsErr := v.WantErr()
+			if !wantsErr {
+				if gotErr != nil {
+					t.Fatalf("%s: expected success, got %s", v.Source(), gotErr)
+				}
+				return
+			}
+
+			if !errors.Is(gotErr, wantErr) {
+				t.Fatalf("%s: expected %s, got %v", v.Source(), v.ExpectedErr, gotErr)
+			}
+		})
+	}
+}
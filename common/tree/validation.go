@@ -5,14 +5,9 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"runtime"
 
-	"github.com/btcsuite/btcd/btcec/v2/schnorr"
-	"github.com/btcsuite/btcd/chaincfg/chainhash"
-	"github.com/decred/dcrd/dcrec/secp256k1/v4"
-	"github.com/vulpemventures/go-elements/elementsutil"
-	"github.com/vulpemventures/go-elements/psetv2"
-	"github.com/vulpemventures/go-elements/taproot"
-	"github.com/vulpemventures/go-elements/transaction"
+	"golang.org/x/sync/errgroup"
 )
 
 var (
@@ -47,41 +42,131 @@ var (
 	ErrWrongPoolTxID            = errors.New("root input should be the pool tx outpoint")
 )
 
+// UnspendablePoint is the canonical NUMS point every tree node's taproot
+// internal key must equal, in 33-byte compressed form. ChainBackend
+// implementations are expected to expose the same point's 32-byte x-only
+// form as their control blocks' internal key.
+const UnspendablePoint = "0250929b74c1a04954b78b4b6035e97a5e078a5a0f28ec96d547bfee9ace803ac0"
+
+func unspendableKeyBytes() []byte {
+	raw, _ := hex.DecodeString(UnspendablePoint)
+	if len(raw) == 0 {
+		return nil
+	}
+	return raw[1:]
+}
+
+// ValidationFlags selectively disables parts of the congestion tree checks,
+// mirroring the flags full-node block validators use to skip re-checking
+// rules a caller already knows hold (e.g. because the input was already
+// validated once and is only being re-verified incrementally).
+type ValidationFlags uint32
+
 const (
-	UnspendablePoint  = "0250929b74c1a04954b78b4b6035e97a5e078a5a0f28ec96d547bfee9ace803ac0"
-	sharedOutputIndex = 0
+	// BFSkipAmounts skips every amount-conservation check: the pool tx vs.
+	// root output sum, each node's children-amount-equals-parent-output
+	// check, and the branch script's per-output amount checks.
+	BFSkipAmounts ValidationFlags = 1 << iota
+	// BFSkipTaprootScripts skips verifying that each tapscript's internal
+	// key is the unspendable point and that its control-block root hash
+	// reconstructs the parent's output script.
+	BFSkipTaprootScripts
+	// BFAssumeRootValid skips linking the tree's root to a pool
+	// transaction outpoint entirely; use it when the root is already
+	// known-good (e.g. it was validated as part of an earlier call and is
+	// only being supplied again as context for ValidateSubtree).
+	BFAssumeRootValid
+	// BFLeavesOnly only runs per-node checks on nodes marked Leaf, trusting
+	// that internal branch nodes were already validated (e.g. by an
+	// earlier, non-incremental call).
+	BFLeavesOnly
+	// BFSkipAssetChecks skips verifying that a node's output assets match
+	// its parent's, independently of BFSkipAmounts: a caller that already
+	// knows values conserve up the tree but still wants every output
+	// checked against the expected asset (or vice versa) can set either
+	// flag without implying the other.
+	BFSkipAssetChecks
 )
 
-// ValidateCongestionTree checks if the given congestion tree is valid
-// poolTxID & poolTxIndex & poolTxAmount are used to validate the root input outpoint
+func (f ValidationFlags) has(flag ValidationFlags) bool {
+	return f&flag != 0
+}
+
+// nodePos locates a node within the tree by level and index, so the
+// parallel validation below can report the same error a sequential,
+// top-to-bottom, left-to-right walk would have reported first.
+type nodePos struct {
+	level, index int
+}
+
+// tapLeafCtx is everything DecodeSweepScript/DecodeBranchScript and the
+// backend's taproot output-key computation can tell us about a single
+// tapscript leaf purely from the node's own control block — none of it
+// depends on which parent output the node spends.
+type tapLeafCtx struct {
+	outputKeyBytes []byte
+
+	isSweep bool
+	aspKey  []byte
+	seconds uint
+
+	isBranch    bool
+	leftKey     []byte
+	rightKey    []byte
+	leftAmount  uint64
+	rightAmount uint64
+}
+
+// nodeCtx is the cached, fully-decoded view of a single tree node. It's
+// built once in the first pass over the tree and is never mutated again, so
+// it's safe for every worker in the second pass to read concurrently.
+type nodeCtx struct {
+	node Node
+
+	outputs []NodeTxOutput
+
+	tapLeaves []tapLeafCtx
+
+	outputSum    uint64
+	outputAssets [][]byte
+
+	// selfErr holds the first error found while decoding and validating
+	// this node in isolation, i.e. everything that can be checked without
+	// looking at the parent node's cached entry.
+	selfErr error
+}
+
+// ValidateCongestionTree checks if the given congestion tree is valid.
+// backend decodes the pool and node transactions for whichever chain the
+// tree was built on (see tree/elements and tree/bitcoin).
 // aspPublicKey & roundLifetimeSeconds are used to validate the sweep tapscript leaves
 // besides that, the function validates:
 // - the number of nodes
 // - the number of leaves
 // - children coherence with parent
-// - every control block and taproot output scripts
+// - every control block and taproot output scripts, including the root's own
 // - input and output amounts
 func ValidateCongestionTree(
+	backend ChainBackend,
 	tree CongestionTree,
 	poolTxHex string,
-	aspPublicKey *secp256k1.PublicKey,
+	aspPublicKey []byte,
 	roundLifetimeSeconds uint,
 ) error {
-	unspendableKeyBytes, _ := hex.DecodeString(UnspendablePoint)
-	unspendableKey, _ := secp256k1.ParsePubKey(unspendableKeyBytes)
-
-	poolTransaction, err := transaction.NewTxFromHex(poolTxHex)
-	if err != nil {
-		return ErrInvalidPoolTransaction
-	}
-
-	poolTxAmount, err := elementsutil.ValueFromBytes(poolTransaction.Outputs[sharedOutputIndex].Value)
-	if err != nil {
-		return ErrInvalidPoolTransaction
-	}
-
-	poolTxID := poolTransaction.TxHash().String()
+	return ValidateCongestionTreeWithFlags(backend, tree, poolTxHex, aspPublicKey, roundLifetimeSeconds, 0)
+}
 
+// ValidateCongestionTreeWithFlags is ValidateCongestionTree with the
+// behavior of individual checks controlled by flags. ValidateCongestionTree
+// is equivalent to ValidateCongestionTreeWithFlags(..., 0).
+func ValidateCongestionTreeWithFlags(
+	backend ChainBackend,
+	tree CongestionTree,
+	poolTxHex string,
+	aspPublicKey []byte,
+	roundLifetimeSeconds uint,
+	flags ValidationFlags,
+) error {
 	nbNodes := tree.NumberOfNodes()
 	if nbNodes == 0 {
 		return ErrEmptyTree
@@ -91,24 +176,58 @@ func ValidateCongestionTree(
 		return ErrInvalidRootLevel
 	}
 
-	// check that root input is connected to the pool tx
-	rootPsetB64 := tree[0][0].Tx
-	rootPset, err := psetv2.NewPsetFromBase64(rootPsetB64)
+	if !flags.has(BFAssumeRootValid) {
+		if err := validateRootAgainstPool(backend, tree, poolTxHex, flags); err != nil {
+			return err
+		}
+	}
+
+	if len(tree.Leaves()) == 0 {
+		return ErrNoLeaves
+	}
+
+	return validateNodes(backend, tree, aspPublicKey, roundLifetimeSeconds, flags)
+}
+
+// validateRootAgainstPool checks that the tree's root node spends the
+// shared pool transaction output, and, unless BFSkipAmounts is set, that the
+// root's outputs add up to the pool output's value.
+func validateRootAgainstPool(backend ChainBackend, tree CongestionTree, poolTxHex string, flags ValidationFlags) error {
+	poolTx, err := backend.ParsePoolTx(poolTxHex)
+	if err != nil {
+		return ErrInvalidPoolTransaction
+	}
+
+	poolTxAmount, err := poolTx.SharedOutputValue()
+	if err != nil {
+		return ErrInvalidPoolTransaction
+	}
+
+	rootTx, err := backend.ParseNodeTx(tree[0][0].Tx)
 	if err != nil {
 		return fmt.Errorf("invalid root transaction: %w", err)
 	}
 
-	if len(rootPset.Inputs) != 1 {
-		return ErrNumberOfInputs
+	rootInput, err := rootTx.Input()
+	if err != nil {
+		return fmt.Errorf("invalid root transaction: %w", err)
 	}
 
-	rootInput := rootPset.Inputs[0]
-	if chainhash.Hash(rootInput.PreviousTxid).String() != poolTxID || rootInput.PreviousTxIndex != sharedOutputIndex {
+	if rootInput.PreviousTxid != poolTx.Txid() {
 		return ErrWrongPoolTxID
 	}
 
+	if flags.has(BFSkipAmounts) {
+		return nil
+	}
+
+	rootOutputs, err := rootTx.Outputs()
+	if err != nil {
+		return fmt.Errorf("invalid root transaction: %w", err)
+	}
+
 	sumRootValue := uint64(0)
-	for _, output := range rootPset.Outputs {
+	for _, output := range rootOutputs {
 		sumRootValue += output.Value
 	}
 
@@ -116,191 +235,486 @@ func ValidateCongestionTree(
 		return ErrInvalidAmount
 	}
 
-	if len(tree.Leaves()) == 0 {
-		return ErrNoLeaves
+	return nil
+}
+
+// validateNodes decodes every node exactly once (phase one) and then checks
+// each node against its cached parent entry on a worker pool sized by
+// GOMAXPROCS (phase two). Workers only ever read from the cache built in
+// phase one and from their own node's parent entry, so no locking is
+// needed once phase one has returned.
+func validateNodes(
+	backend ChainBackend,
+	tree CongestionTree,
+	expectedPublicKeyASP []byte,
+	expectedSequenceSeconds uint,
+	flags ValidationFlags,
+) error {
+	cache := make(map[string]*nodeCtx, tree.NumberOfNodes())
+	order := make([]nodePos, 0, tree.NumberOfNodes())
+
+	for levelIdx, level := range tree {
+		for nodeIdx, node := range level {
+			ctx := buildNodeCtx(backend, tree, node, expectedPublicKeyASP, expectedSequenceSeconds, flags)
+			cache[node.Txid] = ctx
+			order = append(order, nodePos{level: levelIdx, index: nodeIdx})
+		}
 	}
 
-	// iterates over all the nodes of the tree
-	for _, level := range tree {
-		for _, node := range level {
-			if err := validateNodeTransaction(node, tree, unspendableKey, aspPublicKey, roundLifetimeSeconds); err != nil {
-				return err
-			}
+	// Each worker owns a distinct slot of errs (its own index in `order`),
+	// so no locking is needed to collect results back from the pool.
+	errs := make([]error, len(order))
+	g := new(errgroup.Group)
+	g.SetLimit(runtime.GOMAXPROCS(0))
+
+	for i, pos := range order {
+		i := i
+		node := tree[pos.level][pos.index]
+		if flags.has(BFLeavesOnly) && !node.Leaf {
+			continue
+		}
+		g.Go(func() error {
+			errs[i] = validateNodeAgainstParent(node, tree, cache, flags)
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
-func validateNodeTransaction(
-	node Node,
+// buildNodeCtx decodes node.Tx and runs every check that doesn't require
+// the parent node's cached entry, recording the first failure (if any) in
+// selfErr. It runs for every node in the tree, including the root: the
+// root's input carries a sweep and a branch tapscript leaf exactly like any
+// other node's, so it's held to the same internal-key/tapleaf-shape checks
+// as its children rather than being exempted from them.
+func buildNodeCtx(
+	backend ChainBackend,
 	tree CongestionTree,
-	expectedInternalKey,
-	expectedPublicKeyASP *secp256k1.PublicKey,
+	node Node,
+	expectedPublicKeyASP []byte,
 	expectedSequenceSeconds uint,
-) error {
+	flags ValidationFlags,
+) *nodeCtx {
+	ctx := &nodeCtx{node: node}
+
 	if node.Tx == "" {
-		return ErrNodeTransactionEmpty
+		ctx.selfErr = ErrNodeTransactionEmpty
+		return ctx
 	}
 
 	if node.Txid == "" {
-		return ErrNodeTxidEmpty
+		ctx.selfErr = ErrNodeTxidEmpty
+		return ctx
 	}
 
 	if node.ParentTxid == "" {
-		return ErrNodeParentTxidEmpty
+		ctx.selfErr = ErrNodeParentTxidEmpty
+		return ctx
 	}
 
-	decodedPset, err := psetv2.NewPsetFromBase64(node.Tx)
+	decoded, err := backend.ParseNodeTx(node.Tx)
 	if err != nil {
-		return fmt.Errorf("invalid node transaction: %w", err)
+		ctx.selfErr = fmt.Errorf("invalid node transaction: %w", err)
+		return ctx
 	}
 
-	utx, err := decodedPset.UnsignedTx()
+	txid, err := decoded.Txid()
 	if err != nil {
-		return fmt.Errorf("invalid node transaction: %w", err)
+		ctx.selfErr = fmt.Errorf("invalid node transaction: %w", err)
+		return ctx
 	}
-
-	if utx.TxHash().String() != node.Txid {
-		return ErrNodeTxidDifferent
+	if txid != node.Txid {
+		ctx.selfErr = ErrNodeTxidDifferent
+		return ctx
 	}
 
-	if len(decodedPset.Inputs) != 1 {
-		return ErrNumberOfInputs
+	input, err := decoded.Input()
+	if err != nil {
+		ctx.selfErr = fmt.Errorf("invalid node transaction: %w", err)
+		return ctx
+	}
+	if len(input.TapLeaves) != 2 {
+		ctx.selfErr = ErrNumberOfTapscripts
+		return ctx
+	}
+	if input.PreviousTxid != node.ParentTxid {
+		ctx.selfErr = ErrParentTxidInput
+		return ctx
 	}
 
-	input := decodedPset.Inputs[0]
-	if len(input.TapLeafScript) != 2 {
-		return ErrNumberOfTapscripts
+	outputs, err := decoded.Outputs()
+	if err != nil {
+		ctx.selfErr = fmt.Errorf("invalid node transaction: %w", err)
+		return ctx
 	}
+	ctx.outputs = outputs
 
-	if chainhash.Hash(decodedPset.Inputs[0].PreviousTxid).String() != node.ParentTxid {
-		return ErrParentTxidInput
+	feeOutput := outputs[len(outputs)-1]
+	if len(feeOutput.WitnessProgram) != 0 {
+		ctx.selfErr = ErrMissingFeeOutput
+		return ctx
 	}
 
-	feeOutput := decodedPset.Outputs[len(decodedPset.Outputs)-1]
-	if len(feeOutput.Script) != 0 {
-		return ErrMissingFeeOutput
+	if node.Leaf && len(tree.Children(node.Txid)) > 1 {
+		ctx.selfErr = ErrLeafChildren
+		return ctx
 	}
 
-	children := tree.Children(node.Txid)
+	unspendable := unspendableKeyBytes()
+	sweepLeafFound := false
+	branchLeafFound := false
 
-	if node.Leaf && len(children) > 1 {
-		return ErrLeafChildren
-	}
+	for _, tapLeaf := range input.TapLeaves {
+		if !flags.has(BFSkipTaprootScripts) && !bytes.Equal(tapLeaf.InternalKeyBytes, unspendable) {
+			ctx.selfErr = ErrInternalKey
+			return ctx
+		}
 
-	for childIndex, child := range children {
-		childTx, err := psetv2.NewPsetFromBase64(child.Tx)
+		leaf := tapLeafCtx{outputKeyBytes: tapLeaf.OutputKeyBytes}
+
+		isSweepLeaf, aspKey, seconds, err := backend.DecodeSweepScript(tapLeaf.Script)
 		if err != nil {
-			return fmt.Errorf("invalid child transaction: %w", err)
+			ctx.selfErr = fmt.Errorf("invalid sweep script: %w", err)
+			return ctx
 		}
 
-		parentOutput := decodedPset.Outputs[childIndex]
-		previousScriptKey := parentOutput.Script[2:]
-		if len(previousScriptKey) != 32 {
-			return ErrInvalidTaprootScript
+		if isSweepLeaf {
+			if !bytes.Equal(aspKey, expectedPublicKeyASP) {
+				ctx.selfErr = ErrInvalidASP
+				return ctx
+			}
+
+			if seconds != expectedSequenceSeconds {
+				ctx.selfErr = ErrInvalidSweepSequence
+				return ctx
+			}
+
+			leaf.isSweep = true
+			leaf.aspKey = aspKey
+			leaf.seconds = seconds
+			sweepLeafFound = true
+			ctx.tapLeaves = append(ctx.tapLeaves, leaf)
+			continue
 		}
 
-		sweepLeafFound := false
-		branchLeafFound := false
+		isBranchLeaf, leftKey, rightKey, leftAmount, rightAmount, err := backend.DecodeBranchScript(tapLeaf.Script)
+		if err != nil {
+			ctx.selfErr = fmt.Errorf("invalid vtxo script: %w", err)
+			return ctx
+		}
 
-		for _, tapLeaf := range childTx.Inputs[0].TapLeafScript {
-			key := tapLeaf.ControlBlock.InternalKey
-			if !key.IsEqual(expectedInternalKey) {
-				return ErrInternalKey
+		if isBranchLeaf {
+			branchLeafFound = true
+			leaf.isBranch = true
+			leaf.leftKey = leftKey
+			leaf.rightKey = rightKey
+			leaf.leftAmount = leftAmount
+			leaf.rightAmount = rightAmount
+
+			nbOuts := len(outputs)
+			if leftKey != nil && rightKey != nil {
+				if nbOuts != 3 {
+					ctx.selfErr = ErrNumberOfOutputs
+					return ctx
+				}
+			} else {
+				if nbOuts != 2 {
+					ctx.selfErr = ErrNumberOfOutputs
+					return ctx
+				}
 			}
 
-			rootHash := tapLeaf.ControlBlock.RootHash(tapLeaf.Script)
-			outputScript := taproot.ComputeTaprootOutputKey(key, rootHash)
-
-			if !bytes.Equal(schnorr.SerializePubKey(outputScript), previousScriptKey) {
-				return ErrInvalidTaprootScript
+			if !bytes.Equal(outputs[0].WitnessProgram, leftKey) {
+				ctx.selfErr = ErrInvalidLeftOutput
+				return ctx
 			}
 
-			isSweepLeaf, aspKey, seconds, err := decodeSweepScript(tapLeaf.Script)
-			if err != nil {
-				return fmt.Errorf("invalid sweep script: %w", err)
+			if !flags.has(BFSkipAmounts) && leftAmount != outputs[0].Value {
+				ctx.selfErr = ErrInvalidLeftOutput
+				return ctx
 			}
 
-			if isSweepLeaf {
-				if !aspKey.IsEqual(aspKey) {
-					return ErrInvalidASP
+			if rightKey != nil {
+				if !bytes.Equal(outputs[1].WitnessProgram, rightKey) {
+					ctx.selfErr = ErrInvalidRightOutput
+					return ctx
 				}
 
-				if seconds != expectedSequenceSeconds {
-					return ErrInvalidSweepSequence
+				if !flags.has(BFSkipAmounts) && rightAmount != outputs[1].Value {
+					ctx.selfErr = ErrInvalidRightOutput
+					return ctx
 				}
-
-				sweepLeafFound = true
-				continue
 			}
+		}
+
+		ctx.tapLeaves = append(ctx.tapLeaves, leaf)
+	}
+
+	if !sweepLeafFound {
+		ctx.selfErr = ErrMissingSweepTapscript
+		return ctx
+	}
+
+	if !branchLeafFound {
+		ctx.selfErr = ErrMissingBranchTapscript
+		return ctx
+	}
+
+	for _, output := range outputs {
+		ctx.outputSum += output.Value
+		ctx.outputAssets = append(ctx.outputAssets, output.Asset)
+	}
+
+	return ctx
+}
+
+// validateNodeAgainstParent checks node against its parent's cached entry,
+// i.e. everything the original sequential implementation checked while
+// iterating the parent's children: the control-block root hash against the
+// parent's output script, and amount/asset conservation against the
+// parent's output value. The root node has no parent entry in the cache
+// (its parent is the pool transaction, already checked by
+// ValidateCongestionTree), so it's a no-op here.
+func validateNodeAgainstParent(node Node, tree CongestionTree, cache map[string]*nodeCtx, flags ValidationFlags) error {
+	ctx := cache[node.Txid]
+	if ctx.selfErr != nil {
+		return ctx.selfErr
+	}
+
+	parentCtx, isChildOfTreeNode := cache[node.ParentTxid]
+	if !isChildOfTreeNode {
+		return nil
+	}
+	if parentCtx.selfErr != nil {
+		// the parent's own decode/shape error surfaces from its own
+		// position in the tree (under BFLeavesOnly a non-leaf parent is
+		// skipped, so it isn't reported at all); nothing more to check here.
+		return nil
+	}
+
+	siblings := tree.Children(parentCtx.node.Txid)
+	childIndex := -1
+	for i, sibling := range siblings {
+		if sibling.Txid == node.Txid {
+			childIndex = i
+			break
+		}
+	}
+	if childIndex < 0 || childIndex >= len(parentCtx.outputs) {
+		return ErrInvalidChildTxid
+	}
+
+	parentOutput := parentCtx.outputs[childIndex]
+
+	if !flags.has(BFSkipTaprootScripts) {
+		if len(parentOutput.WitnessProgram) != 32 {
+			return ErrInvalidTaprootScript
+		}
 
-			isBranchLeaf, leftKey, rightKey, leftAmount, rightAmount, err := decodeBranchScript(tapLeaf.Script)
-			if err != nil {
-				return fmt.Errorf("invalid vtxo script: %w", err)
+		for _, leaf := range ctx.tapLeaves {
+			if !bytes.Equal(leaf.outputKeyBytes, parentOutput.WitnessProgram) {
+				return ErrInvalidTaprootScript
 			}
+		}
+	}
 
-			if isBranchLeaf {
-				branchLeafFound = true
-
-				// check outputs
-				nbOuts := len(childTx.Outputs)
-				if leftKey != nil && rightKey != nil {
-					if nbOuts != 3 {
-						return ErrNumberOfOutputs
-					}
-				} else {
-					if nbOuts != 2 {
-						return ErrNumberOfOutputs
-					}
-				}
+	if !flags.has(BFSkipAmounts) {
+		if ctx.outputSum != parentOutput.Value {
+			return ErrInvalidAmount
+		}
+	}
 
-				leftWitnessProgram := childTx.Outputs[0].Script[2:]
-				leftOutputAmount := childTx.Outputs[0].Value
+	if !flags.has(BFSkipAssetChecks) {
+		for _, asset := range ctx.outputAssets {
+			if !bytes.Equal(asset, parentOutput.Asset) {
+				return ErrInvalidAsset
+			}
+		}
+	}
 
-				if !bytes.Equal(leftWitnessProgram, schnorr.SerializePubKey(leftKey)) {
-					return ErrInvalidLeftOutput
-				}
+	return nil
+}
 
-				if leftAmount != leftOutputAmount {
-					return ErrInvalidLeftOutput
-				}
+// ValidateSubtree re-validates only rootTxid and its descendants within
+// tree, without re-linking the root to the pool transaction. It's meant for
+// a client that received an updated branch (e.g. after a round participant
+// re-signed it) and only wants to re-verify that branch against the rest of
+// the tree it already validated.
+//
+// If rootTxid isn't tree's own root, ValidateSubtree also looks up its real
+// parent elsewhere in tree (by ParentTxid) and checks the branch root
+// against it with ValidateNode, the same commitment/amount/asset check any
+// other node in the tree gets against its parent - otherwise a re-signed
+// branch could change its root's committed amount or output key relative to
+// its real parent and still validate. tree's own root has no such node to
+// check against (its ParentTxid names the pool transaction, not a tree
+// node); that's the one re-link this function skips, as documented above.
+func ValidateSubtree(
+	backend ChainBackend,
+	tree CongestionTree,
+	rootTxid string,
+	aspPublicKey []byte,
+	roundLifetimeSeconds uint,
+	flags ...ValidationFlags,
+) error {
+	root, ok := nodeByTxid(tree, rootTxid)
+	if !ok {
+		return fmt.Errorf("%w: %s is not a node in the given tree", ErrInvalidChildTxid, rootTxid)
+	}
 
-				if rightKey != nil {
-					rightWitnessProgram := childTx.Outputs[1].Script[2:]
-					rightOutputAmount := childTx.Outputs[1].Value
+	subtree := subtreeRootedAt(tree, root)
+	if err := validateNodes(backend, subtree, aspPublicKey, roundLifetimeSeconds, firstFlags(flags)); err != nil {
+		return err
+	}
 
-					if !bytes.Equal(rightWitnessProgram, schnorr.SerializePubKey(rightKey)) {
-						return ErrInvalidRightOutput
-					}
+	parent, ok := nodeByTxid(tree, root.ParentTxid)
+	if !ok {
+		return nil
+	}
 
-					if rightAmount != rightOutputAmount {
-						return ErrInvalidRightOutput
-					}
-				}
+	return ValidateNode(backend, root, parent, aspPublicKey, roundLifetimeSeconds, flags...)
+}
+
+// nodeByTxid looks up the node with the given txid anywhere in tree.
+func nodeByTxid(tree CongestionTree, txid string) (Node, bool) {
+	for _, level := range tree {
+		for _, node := range level {
+			if node.Txid == txid {
+				return node, true
 			}
 		}
+	}
+	return Node{}, false
+}
 
-		if !sweepLeafFound {
-			return ErrMissingSweepTapscript
+// subtreeRootedAt rebuilds the level structure of tree restricted to root
+// and everything tree.Children reaches from it, so it can be run through the
+// same phase-one/phase-two validation as a full tree.
+func subtreeRootedAt(tree CongestionTree, root Node) CongestionTree {
+	subtree := CongestionTree{{root}}
+	frontier := []string{root.Txid}
+
+	for len(frontier) > 0 {
+		var nextLevel []Node
+		var nextFrontier []string
+		for _, txid := range frontier {
+			for _, child := range tree.Children(txid) {
+				nextLevel = append(nextLevel, child)
+				nextFrontier = append(nextFrontier, child.Txid)
+			}
 		}
+		if len(nextLevel) == 0 {
+			break
+		}
+		subtree = append(subtree, nextLevel)
+		frontier = nextFrontier
+	}
+
+	return subtree
+}
+
+// ValidateNode is a one-shot check for a single node as it streams in
+// during a signing round, before the full tree is assembled. The caller
+// supplies the parent node it claims to extend; ValidateNode re-derives
+// which of the parent's outputs node spends from node's own input rather
+// than from the parent's position in a children list, since the full tree
+// (and therefore tree.Children) isn't available yet.
+func ValidateNode(
+	backend ChainBackend,
+	node Node,
+	parent Node,
+	aspPublicKey []byte,
+	roundLifetimeSeconds uint,
+	flags ...ValidationFlags,
+) error {
+	f := firstFlags(flags)
+
+	ctx := buildNodeCtx(backend, CongestionTree{{node}}, node, aspPublicKey, roundLifetimeSeconds, f)
+	if ctx.selfErr != nil {
+		return ctx.selfErr
+	}
+
+	if node.ParentTxid != parent.Txid {
+		return ErrParentTxidInput
+	}
+
+	parentTx, err := backend.ParseNodeTx(parent.Tx)
+	if err != nil {
+		return fmt.Errorf("invalid parent transaction: %w", err)
+	}
+
+	parentOutputs, err := parentTx.Outputs()
+	if err != nil {
+		return fmt.Errorf("invalid parent transaction: %w", err)
+	}
+
+	childIndex, err := nodeInputIndex(backend, node)
+	if err != nil {
+		return err
+	}
+	if childIndex < 0 || childIndex >= len(parentOutputs) {
+		return ErrInvalidChildTxid
+	}
+	parentOutput := parentOutputs[childIndex]
 
-		if !branchLeafFound {
-			return ErrMissingBranchTapscript
+	if !f.has(BFSkipTaprootScripts) {
+		if len(parentOutput.WitnessProgram) != 32 {
+			return ErrInvalidTaprootScript
 		}
 
-		sumChildAmount := uint64(0)
-		for _, output := range childTx.Outputs {
-			sumChildAmount += output.Value
-			if !bytes.Equal(output.Asset, parentOutput.Asset) {
-				return ErrInvalidAsset
+		for _, leaf := range ctx.tapLeaves {
+			if !bytes.Equal(leaf.outputKeyBytes, parentOutput.WitnessProgram) {
+				return ErrInvalidTaprootScript
 			}
 		}
+	}
 
-		if sumChildAmount != parentOutput.Value {
+	if !f.has(BFSkipAmounts) {
+		if ctx.outputSum != parentOutput.Value {
 			return ErrInvalidAmount
 		}
 	}
 
+	if !f.has(BFSkipAssetChecks) {
+		for _, asset := range ctx.outputAssets {
+			if !bytes.Equal(asset, parentOutput.Asset) {
+				return ErrInvalidAsset
+			}
+		}
+	}
+
 	return nil
-}
\ No newline at end of file
+}
+
+// nodeInputIndex re-decodes node.Tx to read which of its parent's outputs
+// it spends. ValidateNode doesn't have tree.Children available to infer
+// this positionally, so it relies on PreviousTxIndex instead.
+func nodeInputIndex(backend ChainBackend, node Node) (int, error) {
+	decoded, err := backend.ParseNodeTx(node.Tx)
+	if err != nil {
+		return -1, fmt.Errorf("invalid node transaction: %w", err)
+	}
+	input, err := decoded.Input()
+	if err != nil {
+		return -1, fmt.Errorf("invalid node transaction: %w", err)
+	}
+	return input.PreviousTxIndex, nil
+}
+
+// firstFlags returns flags[0], or the zero value if flags is empty, so
+// ValidateSubtree and ValidateNode can take ValidationFlags as a variadic
+// and call sites that pass no flags still compile.
+func firstFlags(flags []ValidationFlags) ValidationFlags {
+	if len(flags) == 0 {
+		return 0
+	}
+	return flags[0]
+}
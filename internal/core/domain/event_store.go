@@ -0,0 +1,24 @@
+package domain
+
+import "context"
+
+// EventFilter narrows which rounds Subscribe delivers events for. The zero
+// value matches every round.
+type EventFilter struct {
+	RoundID string
+}
+
+// EventStore persists and replays the RoundEvent history that drives the
+// round state machine, and lets operators stream newly appended events into
+// external sinks (Kafka, NATS, ...) without polling.
+type EventStore interface {
+	// Append records event as the next event in roundID's history.
+	Append(ctx context.Context, roundID string, event RoundEvent) error
+	// Load returns every event recorded for roundID, in the order they
+	// were appended.
+	Load(ctx context.Context, roundID string) ([]RoundEvent, error)
+	// Subscribe returns a channel fed with events as they're appended,
+	// restricted to rounds matching filter. The channel is closed once
+	// ctx is done.
+	Subscribe(ctx context.Context, filter EventFilter) (<-chan RoundEvent, error)
+}
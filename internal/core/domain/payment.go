@@ -0,0 +1,6 @@
+package domain
+
+// Payment is a single boarding/redeem request registered against a round.
+type Payment struct {
+	Id string
+}
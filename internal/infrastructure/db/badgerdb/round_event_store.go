@@ -0,0 +1,291 @@
+// Package badgerdb provides BadgerDB-backed implementations of this
+// project's storage ports.
+package badgerdb
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	badger "github.com/dgraph-io/badger/v4"
+
+	"github.com/ark-network/ark/internal/core/domain"
+)
+
+const (
+	roundEventsPrefix = "round-events:"
+	roundSeqPrefix    = "round-seq:"
+)
+
+// maxAppendConflictRetries bounds how many times in total (the first try
+// included) Append runs its transaction when it keeps losing an optimistic
+// conflict against a concurrent Append for the same round, before giving up
+// and returning the conflict error.
+const maxAppendConflictRetries = 10
+
+// eventStore is the BadgerDB-backed domain.EventStore. Events for a round
+// are appended under a monotonically increasing per-round key, so Load can
+// replay them in order with a single prefix scan.
+type eventStore struct {
+	db *badger.DB
+
+	mu          sync.Mutex
+	subscribers map[chan domain.RoundEvent]domain.EventFilter
+}
+
+// NewEventStore opens (or creates) a BadgerDB-backed domain.EventStore at
+// path.
+func NewEventStore(path string) (domain.EventStore, error) {
+	db, err := badger.Open(badger.DefaultOptions(path))
+	if err != nil {
+		return nil, fmt.Errorf("opening badger event store at %s: %w", path, err)
+	}
+
+	return &eventStore{
+		db:          db,
+		subscribers: make(map[chan domain.RoundEvent]domain.EventFilter),
+	}, nil
+}
+
+// envelope tags a serialized RoundEvent with its concrete type, so that a
+// new RoundEvent variant added later doesn't change how older log entries
+// decode.
+type envelope struct {
+	Kind string          `json:"kind"`
+	Data json.RawMessage `json:"data"`
+}
+
+func (s *eventStore) Append(_ context.Context, roundID string, event domain.RoundEvent) error {
+	raw, err := marshalEnvelope(event)
+	if err != nil {
+		return err
+	}
+
+	var txErr error
+	for attempt := 0; attempt < maxAppendConflictRetries; attempt++ {
+		txErr = s.db.Update(func(txn *badger.Txn) error {
+			seq, err := nextSeq(txn, roundID)
+			if err != nil {
+				return err
+			}
+			if err := txn.Set(eventKey(roundID, seq), raw); err != nil {
+				return err
+			}
+			return txn.Set(roundSeqKey(roundID), encodeSeq(seq+1))
+		})
+		if !errors.Is(txErr, badger.ErrConflict) {
+			break
+		}
+	}
+	if txErr != nil {
+		return fmt.Errorf("appending event for round %s: %w", roundID, txErr)
+	}
+
+	s.publish(roundID, event)
+	return nil
+}
+
+func (s *eventStore) Load(_ context.Context, roundID string) ([]domain.RoundEvent, error) {
+	var events []domain.RoundEvent
+
+	err := s.db.View(func(txn *badger.Txn) error {
+		prefix := []byte(roundEventsPrefix + roundID + ":")
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = prefix
+
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			var env envelope
+			if err := it.Item().Value(func(val []byte) error {
+				return json.Unmarshal(val, &env)
+			}); err != nil {
+				return err
+			}
+
+			event, err := unmarshalEnvelope(env)
+			if err != nil {
+				return err
+			}
+			events = append(events, event)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("loading events for round %s: %w", roundID, err)
+	}
+
+	return events, nil
+}
+
+func (s *eventStore) Subscribe(ctx context.Context, filter domain.EventFilter) (<-chan domain.RoundEvent, error) {
+	ch := make(chan domain.RoundEvent, 64)
+
+	s.mu.Lock()
+	s.subscribers[ch] = filter
+	s.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		s.mu.Lock()
+		delete(s.subscribers, ch)
+		s.mu.Unlock()
+		close(ch)
+	}()
+
+	return ch, nil
+}
+
+func (s *eventStore) publish(roundID string, event domain.RoundEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for ch, filter := range s.subscribers {
+		if filter.RoundID != "" && filter.RoundID != roundID {
+			continue
+		}
+		select {
+		case ch <- event:
+		default:
+			// A slow subscriber must not block round processing or any
+			// other subscriber; it simply misses this event.
+		}
+	}
+}
+
+// eventKey lays out keys so that a plain byte-order scan over a round's
+// prefix yields events in append order: <prefix><roundID>:<seq, big-endian>.
+func eventKey(roundID string, seq uint64) []byte {
+	key := make([]byte, 0, len(roundEventsPrefix)+len(roundID)+1+8)
+	key = append(key, roundEventsPrefix...)
+	key = append(key, roundID...)
+	key = append(key, ':')
+	seqBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(seqBytes, seq)
+	return append(key, seqBytes...)
+}
+
+// roundSeqKey holds the next sequence number to assign for roundID. Reading
+// it via txn.Get (rather than scanning the round's events with an iterator)
+// is what makes it participate in badger's optimistic conflict detection:
+// badger only tracks conflicts on keys read with Get, so two concurrent
+// Append calls for the same round that both read this key will have one of
+// them fail to commit with badger.ErrConflict instead of silently
+// overwriting each other's event.
+func roundSeqKey(roundID string) []byte {
+	return []byte(roundSeqPrefix + roundID)
+}
+
+func encodeSeq(seq uint64) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, seq)
+	return buf
+}
+
+// nextSeq returns the next sequence number to assign for roundID, or 0 if
+// the round has no events yet.
+func nextSeq(txn *badger.Txn, roundID string) (uint64, error) {
+	item, err := txn.Get(roundSeqKey(roundID))
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var seq uint64
+	err = item.Value(func(val []byte) error {
+		seq = binary.BigEndian.Uint64(val)
+		return nil
+	})
+	return seq, err
+}
+
+// wireRoundFailed mirrors domain.RoundFailed but carries Err as a string,
+// since the error interface doesn't marshal to JSON on its own.
+type wireRoundFailed struct {
+	Id        string
+	Err       string
+	Timestamp int64
+}
+
+func marshalEnvelope(event domain.RoundEvent) ([]byte, error) {
+	var (
+		kind string
+		data []byte
+		err  error
+	)
+
+	switch e := event.(type) {
+	case domain.RoundStarted:
+		kind = "RoundStarted"
+		data, err = json.Marshal(e)
+	case domain.RoundFinalizationStarted:
+		kind = "RoundFinalizationStarted"
+		data, err = json.Marshal(e)
+	case domain.RoundFinalized:
+		kind = "RoundFinalized"
+		data, err = json.Marshal(e)
+	case domain.RoundFailed:
+		kind = "RoundFailed"
+		wireErr := ""
+		if e.Err != nil {
+			wireErr = e.Err.Error()
+		}
+		data, err = json.Marshal(wireRoundFailed{Id: e.Id, Err: wireErr, Timestamp: e.Timestamp})
+	case domain.PaymentsRegistered:
+		kind = "PaymentsRegistered"
+		data, err = json.Marshal(e)
+	case domain.PaymentsClaimed:
+		kind = "PaymentsClaimed"
+		data, err = json.Marshal(e)
+	default:
+		return nil, fmt.Errorf("unknown round event type %T", event)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("marshaling %s event: %w", kind, err)
+	}
+
+	return json.Marshal(envelope{Kind: kind, Data: data})
+}
+
+func unmarshalEnvelope(env envelope) (domain.RoundEvent, error) {
+	switch env.Kind {
+	case "RoundStarted":
+		var e domain.RoundStarted
+		err := json.Unmarshal(env.Data, &e)
+		return e, err
+	case "RoundFinalizationStarted":
+		var e domain.RoundFinalizationStarted
+		err := json.Unmarshal(env.Data, &e)
+		return e, err
+	case "RoundFinalized":
+		var e domain.RoundFinalized
+		err := json.Unmarshal(env.Data, &e)
+		return e, err
+	case "RoundFailed":
+		var wire wireRoundFailed
+		if err := json.Unmarshal(env.Data, &wire); err != nil {
+			return nil, err
+		}
+		e := domain.RoundFailed{Id: wire.Id, Timestamp: wire.Timestamp}
+		if wire.Err != "" {
+			e.Err = errors.New(wire.Err)
+		}
+		return e, nil
+	case "PaymentsRegistered":
+		var e domain.PaymentsRegistered
+		err := json.Unmarshal(env.Data, &e)
+		return e, err
+	case "PaymentsClaimed":
+		var e domain.PaymentsClaimed
+		err := json.Unmarshal(env.Data, &e)
+		return e, err
+	default:
+		return nil, fmt.Errorf("unknown event kind %q in log", env.Kind)
+	}
+}